@@ -0,0 +1,93 @@
+package core
+
+import "image"
+
+// AnchorLayout is a LayoutEngine that places the children with their anchors.
+//
+// On each axis, a child whose two anchors differ is stretched between them:
+// the left edge is at AnchorLeft of the container width plus OffsetLeft,
+// and the right edge is at AnchorRight of the container width minus OffsetRight.
+// A child whose two anchors are equal keeps its own size; its pivot point
+// (PivotX of its width) is placed at the anchor, moved by OffsetLeft - OffsetRight.
+// The vertical axis works the same with the top and bottom properties.
+type AnchorLayout struct{}
+
+var _ LayoutEngine = AnchorLayout{}
+
+// Layout implements LayoutEngine.
+func (AnchorLayout) Layout(container *View, width, height int, items []*LayoutItem) {
+	for _, it := range items {
+		v := it.View
+		w, h := it.Size(width, height)
+		x0, x1 := anchorSpan(
+			width, v.AnchorLeft, v.AnchorRight, v.OffsetLeft, v.OffsetRight, v.PivotX, w)
+		y0, y1 := anchorSpan(
+			height, v.AnchorTop, v.AnchorBottom, v.OffsetTop, v.OffsetBottom, v.PivotY, h)
+		it.SetFrame(image.Rect(x0, y0, x1, y1))
+	}
+}
+
+func anchorSpan(size int, start, end float64, offStart, offEnd int, pivot float64, own int) (int, int) {
+	if start != end {
+		return round(start*float64(size)) + offStart, round(end*float64(size)) - offEnd
+	}
+	p := start*float64(size) + float64(offStart-offEnd)
+	min := round(p - pivot*float64(own))
+	return min, min + own
+}
+
+// AnchorPreset is a common combination of anchors and pivot.
+type AnchorPreset uint8
+
+const (
+	AnchorPresetTopLeft AnchorPreset = iota
+	AnchorPresetTop
+	AnchorPresetTopRight
+	AnchorPresetLeft
+	AnchorPresetCenter
+	AnchorPresetRight
+	AnchorPresetBottomLeft
+	AnchorPresetBottom
+	AnchorPresetBottomRight
+	AnchorPresetFull
+)
+
+func (a AnchorPreset) anchors() (left, top, right, bottom float64) {
+	if a == AnchorPresetFull {
+		return 0, 0, 1, 1
+	}
+	x := [...]float64{0, .5, 1}[int(a)%3]
+	y := [...]float64{0, .5, 1}[int(a)/3]
+	return x, y, x, y
+}
+
+// SetAnchorPreset sets the anchors and the pivot of the view to the preset.
+func (v *View) SetAnchorPreset(preset AnchorPreset) {
+	v.setAnchorPreset(preset)
+	v.Layout()
+}
+
+func (v *View) setAnchorPreset(preset AnchorPreset) {
+	v.AnchorLeft, v.AnchorTop, v.AnchorRight, v.AnchorBottom = preset.anchors()
+	if preset != AnchorPresetFull {
+		v.PivotX, v.PivotY = v.AnchorLeft, v.AnchorTop
+	}
+}
+
+// SetAnchors sets the anchors of the view.
+func (v *View) SetAnchors(left, top, right, bottom float64) {
+	v.AnchorLeft, v.AnchorTop, v.AnchorRight, v.AnchorBottom = left, top, right, bottom
+	v.Layout()
+}
+
+// SetOffsets sets the offsets of the view from its anchors.
+func (v *View) SetOffsets(left, top, right, bottom int) {
+	v.OffsetLeft, v.OffsetTop, v.OffsetRight, v.OffsetBottom = left, top, right, bottom
+	v.Layout()
+}
+
+// SetPivot sets the pivot of the view.
+func (v *View) SetPivot(x, y float64) {
+	v.PivotX, v.PivotY = x, y
+	v.Layout()
+}
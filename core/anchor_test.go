@@ -0,0 +1,111 @@
+package core
+
+import (
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnchorLayout(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		view *View
+		want image.Rectangle
+	}{
+		{
+			name: "top-left",
+			view: &View{Width: 20, Height: 10, OffsetLeft: 16, OffsetTop: 8},
+			want: image.Rect(16, 8, 36, 18),
+		},
+		{
+			name: "top-right",
+			view: &View{
+				Width: 20, Height: 10,
+				AnchorLeft: 1, AnchorRight: 1, PivotX: 1,
+				OffsetRight: 16, OffsetTop: 16,
+			},
+			want: image.Rect(164, 16, 184, 26),
+		},
+		{
+			name: "center",
+			view: &View{
+				Width: 20, Height: 10,
+				AnchorLeft: .5, AnchorTop: .5, AnchorRight: .5, AnchorBottom: .5,
+				PivotX: .5, PivotY: .5,
+			},
+			want: image.Rect(90, 45, 110, 55),
+		},
+		{
+			name: "stretch",
+			view: &View{
+				AnchorLeft: 0, AnchorTop: 1, AnchorRight: 1, AnchorBottom: 1,
+				PivotY:     1,
+				Height:     20,
+				OffsetLeft: 10, OffsetRight: 10, OffsetBottom: 5,
+			},
+			want: image.Rect(10, 75, 190, 95),
+		},
+		{
+			name: "percent size",
+			view: &View{
+				WidthInPct: 50, HeightInPct: 10,
+				AnchorLeft: 1, AnchorTop: 1, AnchorRight: 1, AnchorBottom: 1,
+				PivotX: 1, PivotY: 1,
+			},
+			want: image.Rect(100, 90, 200, 100),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			root := &View{Width: 200, Height: 100, Display: DisplayAnchor}
+			mock := &mockHandler{}
+			tt.view.Handler = mock
+			root.AddChild(tt.view)
+			root.Draw(nil)
+			require.Equal(t, tt.want, mock.Frame)
+
+			root.UpdateWithSize(400, 200)
+			root.Draw(nil)
+			require.Equal(t, tt.view.frame, mock.Frame)
+		})
+	}
+}
+
+func TestAnchorPreset(t *testing.T) {
+	root := &View{Width: 200, Height: 100, Display: DisplayAnchor}
+	mock := &mockHandler{}
+	v := &View{Width: 20, Height: 10, Handler: mock}
+	root.AddChild(v)
+
+	v.SetAnchorPreset(AnchorPresetBottomRight)
+	v.SetOffsets(0, 0, 16, 16)
+	root.Draw(nil)
+	require.Equal(t, image.Rect(164, 74, 184, 84), mock.Frame)
+
+	root.UpdateWithSize(400, 200)
+	root.Draw(nil)
+	require.Equal(t, image.Rect(364, 174, 384, 184), mock.Frame)
+}
+
+func TestParseAnchor(t *testing.T) {
+	resetComponents()
+	v := Parse(`
+		<view style="display: anchor">
+			<view id="hud" style="anchor: top-right; offset-right: 16; offset-top: 16; width: 20; height: 10"></view>
+			<view id="bar" style="anchor-left: 0; anchor-right: 100%; anchor-top: 1; anchor-bottom: 1; pivot-y: 1; height: 10"></view>
+		</view>`, &ParseOptions{Width: 200, Height: 100})
+	require.Equal(t, DisplayAnchor, v.Display)
+
+	hud := v.MustGetByID("hud")
+	require.Equal(t, 1., hud.AnchorLeft)
+	require.Equal(t, 1., hud.AnchorRight)
+	require.Equal(t, 1., hud.PivotX)
+	require.Equal(t, 16, hud.OffsetRight)
+
+	bar := v.MustGetByID("bar")
+	require.Equal(t, 1., bar.AnchorRight)
+
+	v.Update()
+	require.Equal(t, image.Rect(164, 16, 184, 26), hud.frame)
+	require.Equal(t, image.Rect(0, 90, 200, 100), bar.frame)
+}
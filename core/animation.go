@@ -0,0 +1,454 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// now returns the current time. It is replaced in tests.
+var now = time.Now
+
+// Animation is a Tween or a Group of animations. It is played with View.Animate.
+// The interface is sealed: its methods are unexported, so that only the animations of
+// this package implement it. Custom animations are made with Tween.Set and grouped
+// with Sequence and Parallel.
+type Animation interface {
+	// step advances the animation by dt. It returns true and the part of dt
+	// that was not used when the animation has finished.
+	step(dt time.Duration) (rest time.Duration, done bool)
+	// reset rewinds the animation to be played again.
+	reset()
+}
+
+// Property is a numeric property of a view that can be animated.
+type Property int
+
+const (
+	PropertyLeft Property = iota
+	PropertyTop
+	PropertyRight
+	PropertyBottom
+	PropertyWidth
+	PropertyHeight
+	PropertyMarginLeft
+	PropertyMarginTop
+	PropertyMarginRight
+	PropertyMarginBottom
+	PropertyOpacity
+	PropertyTranslateX
+	PropertyTranslateY
+	PropertyScaleX
+	PropertyScaleY
+	PropertyRotate
+	propertyCount
+)
+
+var propertyNames = [propertyCount]string{
+	"left", "top", "right", "bottom", "width", "height",
+	"margin-left", "margin-top", "margin-right", "margin-bottom", "opacity",
+	"translate-x", "translate-y", "scale-x", "scale-y", "rotate",
+}
+
+// String returns the CSS name of the property.
+func (p Property) String() string {
+	if p < 0 || p >= propertyCount {
+		return fmt.Sprintf("Property(%d)", int(p))
+	}
+	return propertyNames[p]
+}
+
+// get returns the value of the property of the view.
+func (p Property) get(v *View) float64 {
+	switch p {
+	case PropertyLeft:
+		return float64(v.Left)
+	case PropertyTop:
+		return float64(v.Top)
+	case PropertyRight:
+		if v.Right != nil {
+			return float64(*v.Right)
+		}
+	case PropertyBottom:
+		if v.Bottom != nil {
+			return float64(*v.Bottom)
+		}
+	case PropertyWidth:
+		return float64(v.Width)
+	case PropertyHeight:
+		return float64(v.Height)
+	case PropertyMarginLeft:
+		return float64(v.MarginLeft)
+	case PropertyMarginTop:
+		return float64(v.MarginTop)
+	case PropertyMarginRight:
+		return float64(v.MarginRight)
+	case PropertyMarginBottom:
+		return float64(v.MarginBottom)
+	case PropertyOpacity:
+		return v.opacity()
+	}
+	t := v.Transform
+	if t == nil {
+		t = NewTransform()
+	}
+	switch p {
+	case PropertyTranslateX:
+		return t.TranslateX
+	case PropertyTranslateY:
+		return t.TranslateY
+	case PropertyScaleX:
+		return 1 + t.ScaleOffsetX
+	case PropertyScaleY:
+		return 1 + t.ScaleOffsetY
+	case PropertyRotate:
+		return t.Rotate
+	}
+	return 0
+}
+
+// isSet reports whether the property has a value. Right and bottom are unset
+// while they are nil and can't be animated from or to their unset value.
+func (p Property) isSet(v *View) bool {
+	switch p {
+	case PropertyRight:
+		return v.Right != nil
+	case PropertyBottom:
+		return v.Bottom != nil
+	}
+	return true
+}
+
+// set sets the property of the view. The layout properties mark the layout dirty.
+func (p Property) set(v *View, value float64) {
+	i := int(math.Round(value))
+	switch p {
+	case PropertyLeft:
+		v.SetLeft(i)
+	case PropertyTop:
+		v.SetTop(i)
+	case PropertyRight:
+		v.SetRight(i)
+	case PropertyBottom:
+		v.SetBottom(i)
+	case PropertyWidth:
+		v.SetWidth(i)
+	case PropertyHeight:
+		v.SetHeight(i)
+	case PropertyMarginLeft:
+		v.SetMarginLeft(i)
+	case PropertyMarginTop:
+		v.SetMarginTop(i)
+	case PropertyMarginRight:
+		v.SetMarginRight(i)
+	case PropertyMarginBottom:
+		v.SetMarginBottom(i)
+	case PropertyOpacity:
+		v.SetOpacity(math.Max(0, math.Min(1, value)))
+	default:
+		// The transform is copied because it can be shared, e.g. with the saved CSS style.
+		t := NewTransform()
+		if v.Transform != nil {
+			*t = *v.Transform
+		}
+		v.Transform = t
+		switch p {
+		case PropertyTranslateX:
+			t.TranslateX = value
+		case PropertyTranslateY:
+			t.TranslateY = value
+		case PropertyScaleX:
+			t.ScaleOffsetX = value - 1
+		case PropertyScaleY:
+			t.ScaleOffsetY = value - 1
+		case PropertyRotate:
+			t.Rotate = value
+		}
+		v.invalidateParent()
+	}
+}
+
+// Tween animates a property of a view, or any value with Set, from From to To.
+type Tween struct {
+	// View and Property are the animated property. They are ignored if Set is not nil.
+	View     *View
+	Property Property
+	// Set sets an arbitrary value, e.g. a field of a handler.
+	Set func(value float64)
+	// Get returns the start value of Set when From is nil.
+	Get func() float64
+
+	// From is the start value. If it is nil, the tween starts from the value
+	// of the property, or of Get, when its delay has elapsed.
+	From *float64
+	To   float64
+
+	Duration time.Duration
+	Delay    time.Duration
+	// Easing is the easing of the tween. It is Linear if nil.
+	Easing Easing
+	// Repeat is the number of times the tween is played again.
+	// The tween loops forever if it is negative.
+	Repeat int
+	// Yoyo plays every other repetition backwards.
+	Yoyo bool
+	// OnComplete is called when the tween has finished.
+	OnComplete func()
+
+	elapsed time.Duration
+	started bool
+	stopped bool
+	from    float64
+}
+
+// Tween returns a tween of the property of the view from its current value to the value.
+func (v *View) Tween(p Property, to float64, d time.Duration) *Tween {
+	return &Tween{View: v, Property: p, To: to, Duration: d}
+}
+
+func (t *Tween) value() float64 {
+	switch {
+	case t.From != nil:
+		return *t.From
+	case t.Set != nil:
+		if t.Get != nil {
+			return t.Get()
+		}
+		return 0
+	case t.View != nil:
+		return t.Property.get(t.View)
+	}
+	return 0
+}
+
+func (t *Tween) apply(value float64) {
+	switch {
+	case t.Set != nil:
+		t.Set(value)
+	case t.View != nil:
+		t.Property.set(t.View, value)
+	}
+}
+
+func (t *Tween) step(dt time.Duration) (time.Duration, bool) {
+	if t.stopped {
+		return dt, true
+	}
+	t.elapsed += dt
+	if t.elapsed < t.Delay {
+		return 0, false
+	}
+	if !t.started {
+		t.started = true
+		t.from = t.value()
+	}
+	active := t.elapsed - t.Delay
+	plays := time.Duration(t.Repeat + 1)
+	if t.Repeat >= 0 && (t.Duration <= 0 || active >= t.Duration*plays) {
+		if t.Yoyo && plays%2 == 0 {
+			t.apply(t.from)
+		} else {
+			t.apply(t.To)
+		}
+		if t.OnComplete != nil {
+			t.OnComplete()
+		}
+		if t.Duration <= 0 {
+			return active, true
+		}
+		return active - t.Duration*plays, true
+	}
+	if t.Duration <= 0 {
+		// An endless tween without a duration only sets its end value.
+		t.apply(t.To)
+		return 0, false
+	}
+	p := float64(active%t.Duration) / float64(t.Duration)
+	if t.Yoyo && (active/t.Duration)%2 == 1 {
+		p = 1 - p
+	}
+	ease := t.Easing
+	if ease == nil {
+		ease = Linear
+	}
+	t.apply(t.from + (t.To-t.from)*ease(p))
+	return 0, false
+}
+
+func (t *Tween) reset() {
+	t.elapsed = 0
+	t.started = false
+}
+
+// Group plays animations one after another or at the same time.
+type Group struct {
+	Animations []Animation
+	// Parallel plays the animations at the same time. The group finishes
+	// when all of them have finished.
+	Parallel bool
+	// Repeat is the number of times the group is played again.
+	// The group loops forever if it is negative.
+	Repeat int
+	// OnComplete is called when the group has finished.
+	OnComplete func()
+
+	current int
+	done    []bool
+	plays   int
+}
+
+// Sequence returns a group that plays the animations one after another.
+func Sequence(anims ...Animation) *Group {
+	return &Group{Animations: anims}
+}
+
+// Parallel returns a group that plays the animations at the same time.
+func Parallel(anims ...Animation) *Group {
+	return &Group{Animations: anims, Parallel: true}
+}
+
+func (g *Group) step(dt time.Duration) (time.Duration, bool) {
+	for {
+		rest, done := g.stepOnce(dt)
+		if !done {
+			return 0, false
+		}
+		g.plays++
+		if g.Repeat >= 0 && g.plays > g.Repeat {
+			if g.OnComplete != nil {
+				g.OnComplete()
+			}
+			return rest, true
+		}
+		if rest == dt && g.Repeat < 0 {
+			// The group takes no time, so it would loop forever in this step.
+			return 0, false
+		}
+		g.rewind()
+		dt = rest
+	}
+}
+
+// stepOnce advances the current play of the group.
+func (g *Group) stepOnce(dt time.Duration) (time.Duration, bool) {
+	if !g.Parallel {
+		for g.current < len(g.Animations) {
+			rest, done := g.Animations[g.current].step(dt)
+			if !done {
+				return 0, false
+			}
+			g.current++
+			dt = rest
+		}
+		return dt, true
+	}
+	if g.done == nil {
+		g.done = make([]bool, len(g.Animations))
+	}
+	rest, all := dt, true
+	for i, a := range g.Animations {
+		if g.done[i] {
+			continue
+		}
+		r, done := a.step(dt)
+		if !done {
+			all = false
+			continue
+		}
+		g.done[i] = true
+		if r < rest {
+			rest = r
+		}
+	}
+	if !all {
+		return 0, false
+	}
+	return rest, true
+}
+
+// rewind restarts the animations of the group for its next play.
+func (g *Group) rewind() {
+	g.current = 0
+	g.done = nil
+	for _, a := range g.Animations {
+		a.reset()
+	}
+}
+
+func (g *Group) reset() {
+	g.rewind()
+	g.plays = 0
+}
+
+// Animate plays the animations. They are advanced by the Update of the root view.
+func (v *View) Animate(anims ...Animation) {
+	v.animations = append(v.animations, anims...)
+}
+
+// StopAnimations stops the animations of the view where they are,
+// including its transitions and keyframe animations. Their OnComplete is not called.
+func (v *View) StopAnimations() {
+	v.animations = nil
+	v.animationsStopped = true
+	for _, p := range v.keyframePlayers {
+		p.finished = true
+	}
+	if v.layoutFlip != nil {
+		v.layoutFlip = nil
+		v.invalidateParent()
+	}
+	exiting := v.exiting
+	v.exiting = nil
+	for _, c := range exiting {
+		v.removeExiting(c)
+	}
+}
+
+// IsAnimating returns true if the view is playing an animation, including its transitions,
+// keyframe animations and the layout animations that move it or make it enter.
+// The exit animation of a removed child is tracked on the parent instead,
+// which keeps animating until the child has been removed.
+func (v *View) IsAnimating() bool {
+	return len(v.animations) > 0
+}
+
+// stepAnimations advances the animations of the view tree by the time
+// since the last update of the root view.
+func (v *View) stepAnimations() {
+	t := now()
+	var dt time.Duration
+	if !v.lastUpdate.IsZero() {
+		dt = t.Sub(v.lastUpdate)
+	}
+	v.lastUpdate = t
+	v.advanceAnimations(dt)
+}
+
+func (v *View) advanceAnimations(dt time.Duration) {
+	if len(v.animations) > 0 {
+		// Animations started by OnComplete are kept for the next update.
+		anims := v.animations
+		v.animations = nil
+		v.animationsStopped = false
+		var playing []Animation
+		for _, a := range anims {
+			if v.animationsStopped {
+				break
+			}
+			if _, done := a.step(dt); !done {
+				playing = append(playing, a)
+			}
+		}
+		if !v.animationsStopped {
+			v.animations = append(playing, v.animations...)
+		}
+		// Animated handler values are only visible if the cache is redrawn.
+		v.Invalidate()
+	}
+	for _, c := range v.children {
+		c.item.advanceAnimations(dt)
+	}
+	for _, c := range v.exiting {
+		c.item.advanceAnimations(dt)
+	}
+}
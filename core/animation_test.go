@@ -0,0 +1,130 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock replaces now until the test ends and returns a function that advances it.
+func fakeClock(t *testing.T) func(d time.Duration) {
+	clock := time.Unix(0, 0)
+	now = func() time.Time { return clock }
+	t.Cleanup(func() { now = time.Now })
+	return func(d time.Duration) { clock = clock.Add(d) }
+}
+
+func TestTweenProperty(t *testing.T) {
+	advance := fakeClock(t)
+	root := &View{Width: 200, Height: 100}
+	box := &View{Width: 0, Height: 10}
+	root.AddChild(box)
+	root.Update()
+
+	box.Animate(box.Tween(PropertyWidth, 100, time.Second))
+	root.Update()
+	require.Equal(t, 0, box.frame.Dx())
+
+	advance(500 * time.Millisecond)
+	root.Update()
+	require.Equal(t, 50, box.Width)
+	require.Equal(t, 50, box.frame.Dx(), "the layout is updated in the same update")
+	require.True(t, box.IsAnimating())
+
+	advance(time.Second)
+	root.Update()
+	require.Equal(t, 100, box.frame.Dx())
+	require.False(t, box.IsAnimating())
+}
+
+func TestTweenTransformAndOpacity(t *testing.T) {
+	advance := fakeClock(t)
+	root := &View{Width: 100, Height: 100}
+	root.Update()
+
+	fade := root.Tween(PropertyOpacity, 0, time.Second)
+	fade.Easing = EaseIn
+	root.Animate(fade, root.Tween(PropertyScaleX, 2, time.Second))
+	root.Update()
+	advance(500 * time.Millisecond)
+	root.Update()
+	require.InDelta(t, 0.75, *root.Opacity, 1e-9)
+	sx, sy := root.Transform.Scale()
+	require.InDelta(t, 1.5, sx, 1e-9)
+	require.Equal(t, 1.0, sy)
+}
+
+func TestTweenSet(t *testing.T) {
+	advance := fakeClock(t)
+	root := &View{Width: 100, Height: 100}
+	value, completed := 0.0, 0
+	root.Animate(&Tween{
+		Get:        func() float64 { return 10 },
+		Set:        func(v float64) { value = v },
+		To:         20,
+		Duration:   time.Second,
+		Delay:      time.Second,
+		Repeat:     1,
+		Yoyo:       true,
+		OnComplete: func() { completed++ },
+	})
+
+	root.Update()
+	advance(time.Second)
+	root.Update()
+	require.Equal(t, 10.0, value)
+	advance(time.Second + 250*time.Millisecond)
+	root.Update()
+	require.Equal(t, 17.5, value, "the repetition plays backwards")
+	advance(time.Second)
+	root.Update()
+	require.Equal(t, 10.0, value)
+	require.Equal(t, 1, completed)
+}
+
+func TestGroup(t *testing.T) {
+	advance := fakeClock(t)
+	root := &View{Width: 100, Height: 100}
+	var log []string
+	a := &Tween{Set: func(float64) {}, Duration: time.Second, OnComplete: func() { log = append(log, "a") }}
+	b := &Tween{Set: func(float64) {}, Duration: time.Second, OnComplete: func() { log = append(log, "b") }}
+	c := &Tween{Set: func(float64) {}, Duration: 3 * time.Second, OnComplete: func() { log = append(log, "c") }}
+	g := Parallel(Sequence(a, b), c)
+	g.Repeat = 1
+	g.OnComplete = func() { log = append(log, "group") }
+	root.Animate(g)
+
+	root.Update()
+	advance(1500 * time.Millisecond)
+	root.Update()
+	require.Equal(t, []string{"a"}, log)
+	advance(time.Second)
+	root.Update()
+	require.Equal(t, []string{"a", "b"}, log)
+	advance(1600 * time.Millisecond)
+	root.Update()
+	require.Equal(t, []string{"a", "b", "c", "a"}, log, "the time left after c starts the repetition")
+	advance(2 * time.Second)
+	root.Update()
+	require.Equal(t, []string{"a", "b", "c", "a", "b", "c", "group"}, log)
+	require.False(t, root.IsAnimating())
+}
+
+func TestStopAnimations(t *testing.T) {
+	advance := fakeClock(t)
+	root := &View{Width: 100, Height: 100}
+	loop := root.Tween(PropertyLeft, 100, time.Second)
+	loop.Repeat = -1
+	root.Animate(loop)
+	root.Update()
+	advance(250 * time.Millisecond)
+	root.Update()
+	require.Equal(t, 25, root.Left)
+
+	root.StopAnimations()
+	advance(250 * time.Millisecond)
+	root.Update()
+	require.Equal(t, 25, root.Left)
+	require.False(t, root.IsAnimating())
+}
@@ -0,0 +1,44 @@
+package core
+
+import (
+	"image"
+)
+
+// hasBox returns true if the view has any box property to paint.
+func (v *View) hasBox() bool {
+	return v.BackgroundColor != nil || v.BackgroundGradient != nil || v.BackgroundImage != "" ||
+		(v.BorderWidth > 0 && v.BorderColor != nil) || len(v.BoxShadows) > 0
+}
+
+func (v *View) shouldPaintBox() bool {
+	if v.Hidden || v.Display == DisplayNone || !v.hasBox() {
+		return false
+	}
+	if v.Handler == nil {
+		return true
+	}
+	h, ok := v.Handler.(BoxPaintHandler)
+	return ok && h.PaintsBox()
+}
+
+// paintBox paints the shadows, the background and the border of the view.
+// The frame is in screen pixels.
+func (v *View) paintBox(r Renderer, frame image.Rectangle) {
+	scale := float32(v.UIScale())
+	radius := float32(v.BorderRadius) * scale
+	if len(v.BoxShadows) > 0 {
+		v.drawBoxShadows(r, frame)
+	}
+	if v.BackgroundColor != nil {
+		r.FillRect(frame, radius, v.BackgroundColor)
+	}
+	if v.BackgroundGradient != nil {
+		r.FillGradient(frame, radius, v.BackgroundGradient)
+	}
+	if v.BackgroundImage != "" {
+		v.drawBackgroundImage(r, frame)
+	}
+	if v.BorderWidth > 0 && v.BorderColor != nil {
+		r.StrokeRect(frame, radius, float32(v.BorderWidth)*scale, v.BorderColor)
+	}
+}
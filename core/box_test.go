@@ -0,0 +1,95 @@
+package core
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseColor(t *testing.T) {
+	for _, tt := range []struct {
+		val  string
+		want color.Color
+	}{
+		{val: "#fff", want: color.NRGBA{0xff, 0xff, 0xff, 0xff}},
+		{val: "#ff000080", want: color.NRGBA{0xff, 0, 0, 0x80}},
+		{val: "#3d550c", want: color.NRGBA{0x3d, 0x55, 0x0c, 0xff}},
+		{val: "rgb(255, 128, 0)", want: color.NRGBA{0xff, 0x80, 0, 0xff}},
+		{val: "rgba(0, 0, 0, 0.5)", want: color.NRGBA{0, 0, 0, 0x80}},
+		{val: "rgb(100%, 0%, 0%)", want: color.NRGBA{0xff, 0, 0, 0xff}},
+		{val: "White", want: color.White},
+	} {
+		c, err := parseColor(tt.val)
+		require.NoError(t, err, tt.val)
+		require.Equal(t, tt.want, c, tt.val)
+	}
+
+	for _, val := range []string{"#12", "rgb(1, 2)", "foo", "#zzzzzz"} {
+		_, err := parseColor(val)
+		require.Error(t, err, val)
+	}
+}
+
+func TestParseBoxStyle(t *testing.T) {
+	resetComponents()
+	v := Parse(`
+		<view style="background-color: #ff0000; border: 2px solid rgba(0, 0, 0, 0.5); border-radius: 8px; opacity: 0.5">
+			<view id="child" style="background: white; border-width: 3; border-color: blue; opacity: 150%"></view>
+			<view id="none" style="border: none"></view>
+		</view>`, nil)
+
+	require.Equal(t, color.NRGBA{0xff, 0, 0, 0xff}, v.BackgroundColor)
+	require.Equal(t, 2, v.BorderWidth)
+	require.Equal(t, color.NRGBA{0, 0, 0, 0x80}, v.BorderColor)
+	require.Equal(t, 8, v.BorderRadius)
+	require.Equal(t, .5, *v.Opacity)
+
+	child := v.MustGetByID("child")
+	require.Equal(t, color.White, child.BackgroundColor)
+	require.Equal(t, 3, child.BorderWidth)
+	require.Equal(t, color.RGBA{0, 0, 0xff, 0xff}, child.BorderColor)
+	require.Equal(t, 1., *child.Opacity)
+
+	none := v.MustGetByID("none")
+	require.Equal(t, 0, none.BorderWidth)
+	require.False(t, none.hasBox())
+}
+
+type boxPaintHandler struct {
+	mockHandler
+	paints bool
+}
+
+func (h *boxPaintHandler) PaintsBox() bool { return h.paints }
+
+func TestShouldPaintBox(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		view *View
+		want bool
+	}{
+		{name: "no box", view: &View{}, want: false},
+		{name: "background", view: &View{BackgroundColor: color.White}, want: true},
+		{name: "border without color", view: &View{BorderWidth: 1}, want: false},
+		{name: "border", view: &View{BorderWidth: 1, BorderColor: color.White}, want: true},
+		{name: "hidden", view: &View{BackgroundColor: color.White, Hidden: true}, want: false},
+		{name: "display none", view: &View{BackgroundColor: color.White, Display: DisplayNone}, want: false},
+		{name: "handler", view: &View{BackgroundColor: color.White, Handler: &mockHandler{}}, want: false},
+		{name: "handler opts in", view: &View{BackgroundColor: color.White, Handler: &boxPaintHandler{paints: true}}, want: true},
+		{name: "handler opts out", view: &View{BackgroundColor: color.White, Handler: &boxPaintHandler{}}, want: false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.view.shouldPaintBox())
+		})
+	}
+}
+
+func TestSplitValues(t *testing.T) {
+	require.Equal(t,
+		[]string{"2px", "solid", "rgba(0, 0, 0, 0.5)"},
+		splitValues(" 2px  solid\trgba(0, 0, 0, 0.5) ", ' '))
+	require.Equal(t,
+		[]string{"a", "rgb(1, 2, 3) 4px"},
+		splitValues("a, rgb(1, 2, 3) 4px", ','))
+}
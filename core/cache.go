@@ -0,0 +1,119 @@
+package core
+
+import (
+	"image"
+	"image/color"
+
+	"golang.org/x/image/font"
+)
+
+// Invalidate marks the render cache of the view and its ancestors as invalid
+// so that they are redrawn on the next frame. Handlers of a cached subtree
+// should call it when their appearance changes.
+func (v *View) Invalidate() {
+	for p := v; p != nil; p = p.parent {
+		p.cacheValid = false
+	}
+}
+
+// invalidateParent invalidates the render caches of the ancestors of the view.
+// It is used for changes that are applied when the view's cache is drawn,
+// such as its opacity or transform.
+func (v *View) invalidateParent() {
+	if v.hasParent {
+		v.parent.Invalidate()
+	}
+}
+
+// SetCache enables or disables the render cache of the view.
+func (v *View) SetCache(cache bool) {
+	v.Cache = cache
+	v.Invalidate()
+}
+
+// drawCached calls draw to draw the view and its descendants with the renderer.
+// If the view is cached, draw renders to the cache image only when the cache
+// is invalid and the cache image is drawn instead. Only a CacheRenderer with
+// a target caches views.
+func (v *View) drawCached(r Renderer, frame image.Rectangle, draw func(r Renderer)) {
+	if !v.Cache {
+		v.disposeCache()
+	}
+	base := r
+	if o, ok := r.(*offsetRenderer); ok {
+		base = o.Renderer
+	}
+	cr, ok := base.(CacheRenderer)
+	if !ok || !v.Cache || v.Hidden || v.Display == DisplayNone || frame.Empty() || r.Target() == nil {
+		draw(r)
+		return
+	}
+	size := frame.Size()
+	if v.cache != nil && (v.cacheOwner != cr || v.cache.Bounds().Size() != size) {
+		v.disposeCache()
+	}
+	if v.cache == nil || !v.cacheValid || v.isDirty {
+		if v.cache == nil {
+			v.cache, v.cacheOwner = cr.NewOffscreen(size.X, size.Y), cr
+		}
+		or := cr.Offscreen(v.cache)
+		draw(&offsetRenderer{Renderer: or, origin: frame.Min})
+		flush(or)
+		v.cacheValid = true
+	}
+	r.DrawImage(v.cache, v.cache.Bounds(), frame, nil)
+}
+
+func (v *View) disposeCache() {
+	if v.cache != nil {
+		v.cacheOwner.DisposeOffscreen(v.cache)
+		v.cache, v.cacheOwner = nil, nil
+	}
+}
+
+// offsetRenderer draws the screen pixels from origin to an offscreen image
+// whose top-left corner is at origin.
+type offsetRenderer struct {
+	Renderer
+	origin image.Point
+}
+
+func (r *offsetRenderer) FillRect(rect image.Rectangle, radius float32, clr color.Color) {
+	r.Renderer.FillRect(rect.Sub(r.origin), radius, clr)
+}
+
+func (r *offsetRenderer) StrokeRect(rect image.Rectangle, radius, width float32, clr color.Color) {
+	r.Renderer.StrokeRect(rect.Sub(r.origin), radius, width, clr)
+}
+
+func (r *offsetRenderer) FillGradient(rect image.Rectangle, radius float32, g *Gradient) {
+	r.Renderer.FillGradient(rect.Sub(r.origin), radius, g)
+}
+
+func (r *offsetRenderer) DrawImage(img Image, src, dst image.Rectangle, opts *ImageOptions) {
+	r.Renderer.DrawImage(img, src, dst.Sub(r.origin), opts)
+}
+
+func (r *offsetRenderer) DrawText(s string, face font.Face, x, y int, clr color.Color) {
+	r.Renderer.DrawText(s, face, x-r.origin.X, y-r.origin.Y, clr)
+}
+
+func (r *offsetRenderer) PushClip(rect image.Rectangle) {
+	r.Renderer.PushClip(rect.Sub(r.origin))
+}
+
+// EndLayer applies the transform of the layer in the coordinates of the screen.
+func (r *offsetRenderer) EndLayer(opts *LayerOptions) {
+	o := *opts
+	o.GeoM = r.toScreen()
+	o.GeoM.Concat(opts.GeoM)
+	o.GeoM.Translate(-float64(r.origin.X), -float64(r.origin.Y))
+	r.Renderer.EndLayer(&o)
+}
+
+// toScreen returns the matrix from the offscreen image to the screen.
+func (r *offsetRenderer) toScreen() GeoM {
+	g := GeoM{}
+	g.Translate(float64(r.origin.X), float64(r.origin.Y))
+	return g
+}
@@ -0,0 +1,110 @@
+package core
+
+import (
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type countingDrawer struct {
+	count int
+	frame image.Rectangle
+}
+
+func (d *countingDrawer) HandleDraw(screen Image, frame image.Rectangle) {
+	d.count++
+	d.frame = frame
+}
+
+// cacheRecorder is a Recorder that caches views in offscreen images like the renderer of an engine.
+type cacheRecorder struct {
+	Recorder
+	target Image
+}
+
+func (r *cacheRecorder) Target() Image { return r.target }
+
+func (r *cacheRecorder) NewOffscreen(width, height int) Image {
+	return image.NewRGBA(image.Rect(0, 0, width, height))
+}
+
+func (r *cacheRecorder) DisposeOffscreen(img Image) {}
+
+func (r *cacheRecorder) Offscreen(img Image) Renderer {
+	return &cacheRecorder{target: img}
+}
+
+func TestRenderCache(t *testing.T) {
+	d := &countingDrawer{}
+	root := &View{Width: 100, Height: 100}
+	menu := &View{Position: PositionAbsolute, Left: 30, Top: 40, Width: 50, Height: 50, Cache: true}
+	item := &View{Width: 10, Height: 10, Handler: d}
+	menu.AddChild(item)
+	root.AddChild(menu)
+
+	r := &cacheRecorder{target: image.NewRGBA(image.Rect(0, 0, 100, 100))}
+	root.Render(r)
+	root.Render(r)
+	require.Equal(t, 1, d.count)
+	require.Equal(t, "image 50x50 (0,0)-(50,50) -> (30,40)-(80,90)", r.Commands[1].String())
+	require.Equal(t, image.Rect(0, 0, 10, 10), d.frame, "the view is drawn in the pixels of the cache")
+	require.Equal(t, image.Rect(0, 0, 50, 50), menu.cache.Bounds(), "the cache has the size of the view")
+
+	item.Invalidate()
+	root.Render(r)
+	require.Equal(t, 2, d.count)
+
+	// Effects of the cached view itself are applied when the cache is drawn.
+	menu.SetOpacity(.5)
+	root.Render(r)
+	require.Equal(t, 2, d.count)
+
+	item.SetWidth(20)
+	root.Render(r)
+	require.Equal(t, 3, d.count)
+
+	menu.AddChild(&View{Width: 10, Height: 10})
+	root.Render(r)
+	require.Equal(t, 4, d.count)
+
+	menu.SetLeft(-20)
+	root.Render(r)
+	require.Equal(t, 5, d.count)
+	require.Equal(t, image.Rect(0, 0, 20, 10), d.frame)
+	require.Equal(t, "image 50x50 (0,0)-(50,50) -> (-20,40)-(30,90)", r.Commands[len(r.Commands)-2].String())
+
+	menu.SetCache(false)
+	root.Render(r)
+	root.Render(r)
+	require.Equal(t, 7, d.count)
+	require.Nil(t, menu.cache)
+}
+
+func TestParseWillChange(t *testing.T) {
+	resetComponents()
+	v := Parse(`
+		<view style="width: 100; height: 100">
+			<view id="menu" style="will-change: contents"></view>
+			<view id="plain" style="will-change: auto"></view>
+		</view>`, nil)
+
+	require.True(t, v.MustGetByID("menu").Cache)
+	require.False(t, v.MustGetByID("plain").Cache)
+}
+
+func TestOffsetRenderer(t *testing.T) {
+	rec := &Recorder{}
+	r := &offsetRenderer{Renderer: rec, origin: image.Pt(10, 20)}
+	r.FillRect(image.Rect(10, 20, 30, 40), 0, nil)
+	require.Equal(t, image.Rect(0, 0, 20, 20), rec.Commands[0].Rect)
+
+	// A layer scaled from the origin of the screen keeps its screen position.
+	g := GeoM{}
+	g.Scale(2, 2)
+	r.BeginLayer()
+	r.EndLayer(&LayerOptions{GeoM: g, Alpha: 1})
+	layer := rec.Commands[2].Layer.GeoM
+	x, y := layer.Apply(0, 0)
+	require.Equal(t, [2]float64{10, 20}, [2]float64{x, y})
+}
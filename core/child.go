@@ -1,11 +1,9 @@
-package furex
+package core
 
 import (
 	"image"
 	"math"
 	"time"
-
-	"github.com/hajimehoshi/ebiten/v2"
 )
 
 type child struct {
@@ -14,9 +12,16 @@ type child struct {
 	bounds                   image.Rectangle
 	isButtonPressed          bool
 	isMouseLeftButtonHandler bool
+	handledMouseButtons      [mouseButtonCount]bool
 	isMouseEntered           bool
-	handledTouchID           ebiten.TouchID
+	isHovered                bool
+	handledTouchID           TouchID
 	swipe
+
+	// exit is the exit animation of a removed child and exitOpacity
+	// is the opacity of the child before it.
+	exit        *Group
+	exitOpacity *float64
 }
 
 type swipe struct {
@@ -25,11 +30,18 @@ type swipe struct {
 	downTime     time.Time
 	upTime       time.Time
 	swipeDir     SwipeDirection
-	swipeTouchID ebiten.TouchID
+	swipeTouchID TouchID
+}
+
+// setBounds sets the bounds of the child relative to the container at origin.
+func (c *child) setBounds(bounds image.Rectangle, origin image.Point) {
+	c.absolute = false
+	c.bounds = bounds
+	c.item.setFrame(bounds.Add(origin))
 }
 
 func (c *child) HandleJustPressedTouchID(
-	frame *image.Rectangle, touchID ebiten.TouchID, x, y int) bool {
+	frame *image.Rectangle, touchID TouchID, x, y int) bool {
 	var result = false
 	if c.checkButtonHandlerStart(frame, touchID, x, y) {
 		result = true
@@ -42,14 +54,14 @@ func (c *child) HandleJustPressedTouchID(
 }
 
 func (c *child) HandleJustReleasedTouchID(
-	frame *image.Rectangle, touchID ebiten.TouchID, x, y int) {
+	frame *image.Rectangle, touchID TouchID, x, y int, unknown bool) {
 	c.checkTouchHandlerEnd(frame, touchID, x, y)
-	c.checkButtonHandlerEnd(frame, touchID, x, y)
+	c.checkButtonHandlerEnd(frame, touchID, x, y, unknown)
 	c.checkSwipeHandlerEnd(frame, touchID, x, y)
 }
 
-func (c *child) checkTouchHandlerStart(frame *image.Rectangle, touchID ebiten.TouchID, x, y int) bool {
-	touchHandler, ok := c.item.Handler.(TouchHandler)
+func (c *child) checkTouchHandlerStart(frame *image.Rectangle, touchID TouchID, x, y int) bool {
+	touchHandler, ok := asTouchHandler(c.item.Handler)
 	if ok {
 		if isInside(frame, x, y) {
 			if touchHandler.HandleJustPressedTouchID(touchID, x, y) {
@@ -61,8 +73,8 @@ func (c *child) checkTouchHandlerStart(frame *image.Rectangle, touchID ebiten.To
 	return false
 }
 
-func (c *child) checkTouchHandlerEnd(frame *image.Rectangle, touchID ebiten.TouchID, x, y int) {
-	touchHandler, ok := c.item.Handler.(TouchHandler)
+func (c *child) checkTouchHandlerEnd(frame *image.Rectangle, touchID TouchID, x, y int) {
+	touchHandler, ok := asTouchHandler(c.item.Handler)
 	if ok {
 		if c.handledTouchID == touchID {
 			touchHandler.HandleJustReleasedTouchID(touchID, x, y)
@@ -71,7 +83,7 @@ func (c *child) checkTouchHandlerEnd(frame *image.Rectangle, touchID ebiten.Touc
 	}
 }
 
-func (c *child) checkSwipeHandlerStart(frame *image.Rectangle, touchID ebiten.TouchID, x, y int) bool {
+func (c *child) checkSwipeHandlerStart(frame *image.Rectangle, touchID TouchID, x, y int) bool {
 	_, ok := c.item.Handler.(SwipeHandler)
 	if ok {
 		if isInside(frame, x, y) {
@@ -84,7 +96,7 @@ func (c *child) checkSwipeHandlerStart(frame *image.Rectangle, touchID ebiten.To
 	return false
 }
 
-func (c *child) checkSwipeHandlerEnd(frame *image.Rectangle, touchID ebiten.TouchID, x, y int) bool {
+func (c *child) checkSwipeHandlerEnd(frame *image.Rectangle, touchID TouchID, x, y int) bool {
 	swipeHandler, ok := c.item.Handler.(SwipeHandler)
 	if ok {
 		if c.swipeTouchID != touchID {
@@ -133,8 +145,8 @@ func (c *child) checkSwipe() bool {
 	return false
 }
 
-func (c *child) checkButtonHandlerStart(frame *image.Rectangle, touchID ebiten.TouchID, x, y int) bool {
-	button, ok := c.item.Handler.(ButtonHandler)
+func (c *child) checkButtonHandlerStart(frame *image.Rectangle, touchID TouchID, x, y int) bool {
+	button, ok := asButtonHandler(c.item.Handler)
 	if ok {
 		for {
 			if button, ok := c.item.Handler.(NotButton); ok {
@@ -158,14 +170,14 @@ func (c *child) checkButtonHandlerStart(frame *image.Rectangle, touchID ebiten.T
 	return false
 }
 
-func (c *child) checkButtonHandlerEnd(frame *image.Rectangle, touchID ebiten.TouchID, x, y int) {
-	button, ok := c.item.Handler.(ButtonHandler)
+func (c *child) checkButtonHandlerEnd(frame *image.Rectangle, touchID TouchID, x, y int, unknown bool) {
+	button, ok := asButtonHandler(c.item.Handler)
 	if ok {
 		if c.handledTouchID == touchID {
 			if c.isButtonPressed {
 				c.isButtonPressed = false
 				c.handledTouchID = -1
-				if x == 0 && y == 0 {
+				if unknown {
 					button.HandleRelease(x, y, false)
 				} else {
 					button.HandleRelease(x, y, !isInside(frame, x, y))
@@ -1,11 +1,10 @@
-package furex
+package core
 
 import (
 	"image"
 	"testing"
 	"time"
 
-	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -361,12 +360,12 @@ func (h *mockHandler) HandleUpdate() {
 	h.IsUpdated = true
 }
 
-func (h *mockHandler) HandleDraw(screen *ebiten.Image, frame image.Rectangle) {
+func (h *mockHandler) HandleDraw(screen Image, frame image.Rectangle) {
 	h.Frame = frame
 	h.IsDrawn = true
 }
 
-func (h *mockHandler) HandlePress(x, y int, t ebiten.TouchID) {
+func (h *mockHandler) HandlePress(x, y int, t TouchID) {
 	h.IsPressed = true
 }
 
@@ -0,0 +1,114 @@
+package core
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+var namedColors = map[string]color.Color{
+	"transparent": color.Transparent,
+	"black":       color.Black,
+	"white":       color.White,
+	"gray":        color.RGBA{0x80, 0x80, 0x80, 0xff},
+	"grey":        color.RGBA{0x80, 0x80, 0x80, 0xff},
+	"silver":      color.RGBA{0xc0, 0xc0, 0xc0, 0xff},
+	"red":         color.RGBA{0xff, 0, 0, 0xff},
+	"maroon":      color.RGBA{0x80, 0, 0, 0xff},
+	"orange":      color.RGBA{0xff, 0xa5, 0, 0xff},
+	"yellow":      color.RGBA{0xff, 0xff, 0, 0xff},
+	"olive":       color.RGBA{0x80, 0x80, 0, 0xff},
+	"lime":        color.RGBA{0, 0xff, 0, 0xff},
+	"green":       color.RGBA{0, 0x80, 0, 0xff},
+	"aqua":        color.RGBA{0, 0xff, 0xff, 0xff},
+	"cyan":        color.RGBA{0, 0xff, 0xff, 0xff},
+	"teal":        color.RGBA{0, 0x80, 0x80, 0xff},
+	"blue":        color.RGBA{0, 0, 0xff, 0xff},
+	"navy":        color.RGBA{0, 0, 0x80, 0xff},
+	"fuchsia":     color.RGBA{0xff, 0, 0xff, 0xff},
+	"magenta":     color.RGBA{0xff, 0, 0xff, 0xff},
+	"purple":      color.RGBA{0x80, 0, 0x80, 0xff},
+}
+
+// parseColor parses a CSS color such as `#fff`, `#ff000080`, `rgb(255, 0, 0)`,
+// `rgba(255, 0, 0, 0.5)` or a color name.
+func parseColor(val string) (any, error) {
+	val = strings.ToLower(strings.TrimSpace(val))
+	if c, ok := namedColors[val]; ok {
+		return c, nil
+	}
+	switch {
+	case strings.HasPrefix(val, "#"):
+		return parseHexColor(val[1:])
+	case strings.HasPrefix(val, "rgb(") || strings.HasPrefix(val, "rgba("):
+		return parseRGBColor(val)
+	}
+	return nil, fmt.Errorf("unknown color: %s", val)
+}
+
+func parseHexColor(hex string) (color.Color, error) {
+	if len(hex) == 3 || len(hex) == 4 {
+		expanded := make([]byte, 0, len(hex)*2)
+		for i := 0; i < len(hex); i++ {
+			expanded = append(expanded, hex[i], hex[i])
+		}
+		hex = string(expanded)
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	if len(hex) != 8 {
+		return nil, fmt.Errorf("invalid color: #%s", hex)
+	}
+	n, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid color: #%s", hex)
+	}
+	return color.NRGBA{uint8(n >> 24), uint8(n >> 16), uint8(n >> 8), uint8(n)}, nil
+}
+
+func parseRGBColor(val string) (color.Color, error) {
+	open, close := strings.Index(val, "("), strings.LastIndex(val, ")")
+	if close < open {
+		return nil, fmt.Errorf("invalid color: %s", val)
+	}
+	args := strings.Split(val[open+1:close], ",")
+	if len(args) != 3 && len(args) != 4 {
+		return nil, fmt.Errorf("invalid color: %s", val)
+	}
+	var rgba [4]uint8
+	rgba[3] = 0xff
+	for i, arg := range args {
+		arg = strings.TrimSpace(arg)
+		var (
+			f   float64
+			err error
+		)
+		switch {
+		case strings.HasSuffix(arg, "%"):
+			f, err = strconv.ParseFloat(strings.TrimSuffix(arg, "%"), 64)
+			f = f / 100 * 0xff
+		case i == 3:
+			f, err = strconv.ParseFloat(arg, 64)
+			f *= 0xff
+		default:
+			f, err = strconv.ParseFloat(arg, 64)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid color: %s", val)
+		}
+		rgba[i] = uint8(clamp(round(f), 0, 0xff))
+	}
+	return color.NRGBA{rgba[0], rgba[1], rgba[2], rgba[3]}, nil
+}
+
+func clamp(v, min, max int) int {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
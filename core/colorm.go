@@ -0,0 +1,154 @@
+package core
+
+import (
+	"image/color"
+	"math"
+)
+
+// ColorM is a matrix that transforms colors. It has the same semantics as ebiten.ColorM:
+// the zero value is the identity and each operation is applied after the current matrix.
+// The matrix has 4 rows for red, green, blue and alpha, and 5 columns where the last
+// column is the translation. It works on colors with non-premultiplied alpha.
+type ColorM struct {
+	// diff is the matrix minus the identity so that the zero value is the identity.
+	diff [4][5]float64
+}
+
+var (
+	rgbToYCbCr = [4][5]float64{
+		{0.2990, 0.5870, 0.1140, 0, 0},
+		{-0.1687, -0.3313, 0.5000, 0, 0},
+		{0.5000, -0.4187, -0.0813, 0, 0},
+		{0, 0, 0, 1, 0},
+	}
+	yCbCrToRgb = [4][5]float64{
+		{1, 0, 1.40200, 0, 0},
+		{1, -0.34414, -0.71414, 0, 0},
+		{1, 1.77200, 0, 0, 0},
+		{0, 0, 0, 1, 0},
+	}
+)
+
+// Element returns the element of the matrix at (i, j).
+func (c *ColorM) Element(i, j int) float64 {
+	if i == j {
+		return c.diff[i][j] + 1
+	}
+	return c.diff[i][j]
+}
+
+// SetElement sets the element of the matrix at (i, j).
+func (c *ColorM) SetElement(i, j int, element float64) {
+	if i == j {
+		element--
+	}
+	c.diff[i][j] = element
+}
+
+// IsIdentity returns true if the matrix is the identity.
+func (c *ColorM) IsIdentity() bool {
+	return c.diff == [4][5]float64{}
+}
+
+func (c *ColorM) elements() [4][5]float64 {
+	m := c.diff
+	for i := 0; i < 4; i++ {
+		m[i][i]++
+	}
+	return m
+}
+
+func (c *ColorM) setElements(m [4][5]float64) {
+	for i := 0; i < 4; i++ {
+		m[i][i]--
+	}
+	c.diff = m
+}
+
+// Concat multiplies the matrix by other on the left, so that other is applied after it.
+func (c *ColorM) Concat(other ColorM) {
+	c.concat(other.elements())
+}
+
+func (c *ColorM) concat(o [4][5]float64) {
+	m := c.elements()
+	var r [4][5]float64
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 5; j++ {
+			for k := 0; k < 4; k++ {
+				r[i][j] += o[i][k] * m[k][j]
+			}
+		}
+		r[i][4] += o[i][4]
+	}
+	c.setElements(r)
+}
+
+// Scale scales the red, green, blue and alpha of the matrix.
+func (c *ColorM) Scale(r, g, b, a float64) {
+	s := [4]float64{r, g, b, a}
+	m := c.elements()
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 5; j++ {
+			m[i][j] *= s[i]
+		}
+	}
+	c.setElements(m)
+}
+
+// ScaleWithColor scales the matrix by the non-premultiplied color.
+func (c *ColorM) ScaleWithColor(clr color.Color) {
+	n := color.NRGBA64Model.Convert(clr).(color.NRGBA64)
+	c.Scale(float64(n.R)/0xffff, float64(n.G)/0xffff, float64(n.B)/0xffff, float64(n.A)/0xffff)
+}
+
+// Translate translates the red, green, blue and alpha of the matrix.
+func (c *ColorM) Translate(r, g, b, a float64) {
+	c.diff[0][4] += r
+	c.diff[1][4] += g
+	c.diff[2][4] += b
+	c.diff[3][4] += a
+}
+
+// RotateHue rotates the hue by theta in radians.
+func (c *ColorM) RotateHue(theta float64) {
+	c.ChangeHSV(theta, 1, 1)
+}
+
+// ChangeHSV rotates the hue by hueTheta in radians and scales the saturation and the value.
+func (c *ColorM) ChangeHSV(hueTheta, saturationScale, valueScale float64) {
+	if hueTheta == 0 && saturationScale == 1 {
+		c.Scale(valueScale, valueScale, valueScale, 1)
+		return
+	}
+	sin, cos := math.Sincos(hueTheta)
+	c.concat(rgbToYCbCr)
+	c.concat([4][5]float64{
+		{1, 0, 0, 0, 0},
+		{0, cos, -sin, 0, 0},
+		{0, sin, cos, 0, 0},
+		{0, 0, 0, 1, 0},
+	})
+	s, v := saturationScale, valueScale
+	c.Scale(v, s*v, s*v, 1)
+	c.concat(yCbCrToRgb)
+}
+
+// Apply returns the color transformed by the matrix.
+func (c *ColorM) Apply(clr color.Color) color.Color {
+	r, g, b, a := clr.RGBA()
+	var in [4]float64
+	if a > 0 {
+		in = [4]float64{float64(r) / float64(a), float64(g) / float64(a), float64(b) / float64(a), float64(a) / 0xffff}
+	}
+	m := c.elements()
+	var out [4]uint16
+	for i := 0; i < 4; i++ {
+		v := m[i][4]
+		for j := 0; j < 4; j++ {
+			v += m[i][j] * in[j]
+		}
+		out[i] = uint16(math.Max(0, math.Min(1, v)) * 0xffff)
+	}
+	return color.NRGBA64{R: out[0], G: out[1], B: out[2], A: out[3]}
+}
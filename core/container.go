@@ -0,0 +1,443 @@
+package core
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"golang.org/x/image/font/basicfont"
+)
+
+type containerEmbed struct {
+	children []*child
+	isDirty  bool
+	frame    image.Rectangle
+	touchIDs []TouchID
+	cursor   image.Point
+	// hasCursor is true after the position of the cursor has been read.
+	hasCursor bool
+
+	calculatedWidth  int
+	calculatedHeight int
+}
+
+// processEvent dispatches the input events to the children.
+// The positions are converted to logical pixels and then by toLocal.
+// The pointer events are dispatched first, and the children handle a pointer
+// down only if dispatch returns true.
+func (ct *containerEmbed) processEvent(scale float64, toLocal func(x, y int) (int, int), dispatch func(e *Event) bool) {
+	ct.handleTouchEvents(scale, toLocal, dispatch)
+	ct.handleMouseEvents(scale, toLocal, dispatch)
+}
+
+// draw draws it's children
+func (ct *containerEmbed) draw(r Renderer) {
+	for _, c := range ct.children {
+		ct.drawChild(r, c)
+	}
+}
+
+func (ct *containerEmbed) drawChild(r Renderer, child *child) {
+	b := toPhysicalRect(ct.computeBounds(child), child.item.UIScale())
+	effect := child.item.beginEffect(r)
+	child.item.drawCached(r, child.item.boxShadowBounds(b), func(r Renderer) {
+		if child.item.shouldPaintBox() {
+			child.item.paintBox(r, b)
+		}
+		if child.item.shouldPaintText() {
+			child.item.paintText(r, b)
+		}
+		if ct.shouldDrawChild(child) {
+			ct.handleDraw(r, b, child)
+		}
+		child.item.Render(r)
+	})
+	child.item.endEffect(r, effect)
+	ct.debugDraw(r, b, child)
+}
+
+func (ct *containerEmbed) computeBounds(child *child) image.Rectangle {
+	if child.absolute {
+		return child.bounds
+	}
+	return child.bounds.Add(ct.frame.Min)
+}
+
+func (ct *containerEmbed) handleDraw(r Renderer, b image.Rectangle, child *child) {
+	child.item.drawHandler(child.item.newDrawContext(r, b, child))
+}
+
+func (ct *containerEmbed) shouldDrawChild(child *child) bool {
+	return !child.item.Hidden && child.item.Display != DisplayNone && child.item.Handler != nil
+}
+
+func (ct *containerEmbed) debugDraw(r Renderer, b image.Rectangle, child *child) {
+	if isDebug() && r.Target() != nil {
+		pos := fmt.Sprintf("(%d, %d)-(%d, %d):%s:%s", b.Min.X, b.Min.Y, b.Max.X, b.Max.Y, child.item.TagName, child.item.ID)
+		face := basicfont.Face7x13
+		r.FillRect(image.Rect(b.Min.X, b.Min.Y, b.Min.X+len(pos)*face.Advance, b.Min.Y+face.Height), 0, color.RGBA{0, 0, 0, 200})
+		r.DrawText(pos, face, b.Min.X, b.Min.Y+face.Ascent, color.White)
+	}
+}
+
+func (ct *containerEmbed) HandleJustPressedTouchID(touchID TouchID, x, y int) bool {
+	for c := len(ct.children) - 1; c >= 0; c-- {
+		child := ct.children[c]
+		childFrame := ct.childFrame(child)
+		if child.item.Display == DisplayNone {
+			continue
+		}
+		x, y := child.toLocal(x, y)
+		if child.HandleJustPressedTouchID(childFrame, touchID, x, y) {
+			return true
+		}
+		if child.item.HandleJustPressedTouchID(touchID, x, y) {
+			return true
+		}
+	}
+	return false
+}
+
+func (ct *containerEmbed) HandleJustReleasedTouchID(touchID TouchID, x, y int) {
+	// (0, 0) is the position of a released touch whose position is unknown.
+	ct.releaseTouchID(touchID, x, y, x == 0 && y == 0)
+}
+
+func (ct *containerEmbed) releaseTouchID(touchID TouchID, x, y int, unknown bool) {
+	for c := len(ct.children) - 1; c >= 0; c-- {
+		child := ct.children[c]
+		childFrame := ct.childFrame(child)
+		x, y := child.toLocal(x, y)
+		child.HandleJustReleasedTouchID(childFrame, touchID, x, y, unknown)
+		child.item.releaseTouchID(touchID, x, y, unknown)
+	}
+}
+
+func (ct *containerEmbed) handleMouse(x, y int) bool {
+	for c := len(ct.children) - 1; c >= 0; c-- {
+		child := ct.children[c]
+		childFrame := ct.childFrame(child)
+		if child.item.Display == DisplayNone {
+			continue
+		}
+		x, y := child.toLocal(x, y)
+		mouseHandler, ok := child.item.Handler.(MouseHandler)
+		if ok && mouseHandler != nil {
+			if isInside(childFrame, x, y) {
+				if mouseHandler.HandleMouse(x, y) {
+					return true
+				}
+			}
+		}
+		if child.item.handleMouse(x, y) {
+			return true
+		}
+	}
+	return false
+}
+
+// handleWheel passes the scroll to the topmost child under the cursor and then to
+// its ancestors until one of them handles it. hit is true if a child is under the cursor.
+func (ct *containerEmbed) handleWheel(x, y int, dx, dy float64) (handled, hit bool) {
+	for c := len(ct.children) - 1; c >= 0; c-- {
+		child := ct.children[c]
+		if child.item.Display == DisplayNone {
+			continue
+		}
+		x, y := child.toLocal(x, y)
+		handled, hit := child.item.handleWheel(x, y, dx, dy)
+		if handled {
+			return true, true
+		}
+		if !hit && !isInside(ct.childFrame(child), x, y) {
+			continue
+		}
+		if h, ok := child.item.Handler.(WheelHandler); ok && h.HandleWheel(x, y, dx, dy) {
+			return true, true
+		}
+		return false, true
+	}
+	return false, false
+}
+
+func (ct *containerEmbed) handleMouseEnterLeave(x, y int) bool {
+	result := false
+	for c := len(ct.children) - 1; c >= 0; c-- {
+		child := ct.children[c]
+		childFrame := ct.childFrame(child)
+		if child.item.Display == DisplayNone {
+			child.isHovered = false
+			continue
+		}
+		x, y := child.toLocal(x, y)
+		child.isHovered = isInside(childFrame, x, y)
+		mouseHandler, ok := child.item.Handler.(MouseEnterLeaveHandler)
+		if ok {
+			if !result && !child.isMouseEntered && isInside(childFrame, x, y) {
+				if mouseHandler.HandleMouseEnter(x, y) {
+					result = true
+					child.isMouseEntered = true
+				}
+			}
+
+			if child.isMouseEntered && !isInside(childFrame, x, y) {
+				child.isMouseEntered = false
+				mouseHandler.HandleMouseLeave()
+			}
+		}
+
+		if child.item.handleMouseEnterLeave(x, y) {
+			result = true
+		}
+	}
+	return result
+}
+
+func (ct *containerEmbed) handleMouseButtonLeftPressed(x, y int) bool {
+	result := false
+
+	for c := len(ct.children) - 1; c >= 0; c-- {
+		child := ct.children[c]
+		childFrame := ct.childFrame(child)
+		if child.item.Display == DisplayNone {
+			continue
+		}
+		x, y := child.toLocal(x, y)
+		mouseLeftClickHandler, ok := child.item.Handler.(MouseLeftButtonHandler)
+		if ok {
+			if !result && isInside(childFrame, x, y) {
+				if mouseLeftClickHandler.HandleJustPressedMouseButtonLeft(x, y) {
+					result = true
+					child.isMouseLeftButtonHandler = true
+				}
+			}
+		}
+
+		button, ok := asButtonHandler(child.item.Handler)
+		if ok {
+			for {
+				if button, ok := child.item.Handler.(NotButton); ok {
+					if !button.IsButton() {
+						break
+					}
+				}
+				if !result && isInside(childFrame, x, y) {
+					if !child.isButtonPressed {
+						child.isButtonPressed = true
+						child.isMouseLeftButtonHandler = true
+						result = true
+						button.HandlePress(x, y, -1)
+					}
+				}
+				break
+			}
+		}
+
+		if !result && isInside(childFrame, x, y) && child.pressMouseButton(MouseButtonLeft, x, y) {
+			result = true
+		}
+
+		if !result && child.item.handleMouseButtonLeftPressed(x, y) {
+			result = true
+		}
+	}
+	return result
+}
+
+func (ct *containerEmbed) handleMouseButtonLeftReleased(x, y int) {
+	// (0, 0) is the position of a release whose position is unknown: it cancels the buttons.
+	ct.releaseMouseButtonLeft(x, y, x == 0 && y == 0)
+}
+
+func (ct *containerEmbed) releaseMouseButtonLeft(x, y int, unknown bool) {
+	for c := len(ct.children) - 1; c >= 0; c-- {
+		child := ct.children[c]
+		x, y := child.toLocal(x, y)
+		mouseLeftClickHandler, ok := child.item.Handler.(MouseLeftButtonHandler)
+		if ok {
+			if child.isMouseLeftButtonHandler {
+				child.isMouseLeftButtonHandler = false
+				mouseLeftClickHandler.HandleJustReleasedMouseButtonLeft(x, y)
+			}
+		}
+
+		button, ok := asButtonHandler(child.item.Handler)
+		if ok {
+			if child.isButtonPressed && child.isMouseLeftButtonHandler {
+				child.isButtonPressed = false
+				child.isMouseLeftButtonHandler = false
+				if unknown {
+					button.HandleRelease(x, y, true)
+				} else {
+					button.HandleRelease(x, y, !isInside(ct.childFrame(child), x, y))
+				}
+			}
+		}
+
+		child.item.releaseMouseButtonLeft(x, y, unknown)
+	}
+}
+
+func (ct *containerEmbed) handleMouseButtonPressed(b MouseButton, x, y int) bool {
+	result := false
+	for c := len(ct.children) - 1; c >= 0; c-- {
+		child := ct.children[c]
+		childFrame := ct.childFrame(child)
+		if child.item.Display == DisplayNone {
+			continue
+		}
+		x, y := child.toLocal(x, y)
+		if !result && isInside(childFrame, x, y) && child.pressMouseButton(b, x, y) {
+			result = true
+		}
+		if !result && child.item.handleMouseButtonPressed(b, x, y) {
+			result = true
+		}
+	}
+	return result
+}
+
+// isMouseButtonPressed returns true if a press of a mouse button has been handled
+// by the MouseButtonHandler of the child and not released yet.
+func (c *child) isMouseButtonPressed() bool {
+	for _, pressed := range c.handledMouseButtons {
+		if pressed {
+			return true
+		}
+	}
+	return false
+}
+
+// pressMouseButton calls the MouseButtonHandler of the child and returns true if it handles the press.
+func (c *child) pressMouseButton(b MouseButton, x, y int) bool {
+	h, ok := asMouseButtonHandler(c.item.Handler)
+	if !ok || c.handledMouseButtons[b] || !h.HandleJustPressedMouseButton(b, x, y) {
+		return false
+	}
+	c.handledMouseButtons[b] = true
+	return true
+}
+
+func (ct *containerEmbed) handleMouseButtonReleased(b MouseButton, x, y int) {
+	// (0, 0) is the position of a release whose position is unknown: it cancels the press.
+	ct.releaseMouseButton(b, x, y, x == 0 && y == 0)
+}
+
+func (ct *containerEmbed) releaseMouseButton(b MouseButton, x, y int, unknown bool) {
+	for c := len(ct.children) - 1; c >= 0; c-- {
+		child := ct.children[c]
+		x, y := child.toLocal(x, y)
+		if h, ok := asMouseButtonHandler(child.item.Handler); ok && child.handledMouseButtons[b] {
+			child.handledMouseButtons[b] = false
+			h.HandleJustReleasedMouseButton(b, x, y, unknown || !isInside(ct.childFrame(child), x, y))
+		}
+		child.item.releaseMouseButton(b, x, y, unknown)
+	}
+}
+
+func isInside(r *image.Rectangle, x, y int) bool {
+	return r.Min.X <= x && x <= r.Max.X && r.Min.Y <= y && y <= r.Max.Y
+}
+
+func (ct *containerEmbed) handleTouchEvents(scale float64, toLocal func(x, y int) (int, int), dispatch func(e *Event) bool) {
+	justPressedTouchIds := input.AppendJustPressedTouchIDs(nil)
+
+	if justPressedTouchIds != nil {
+		for i := 0; i < len(justPressedTouchIds); i++ {
+			touchID := justPressedTouchIds[i]
+			x, y := input.TouchPosition(touchID)
+			x, y = toLocal(toLogicalPoint(x, y, scale))
+			recordTouchPosition(touchID, x, y)
+
+			if dispatch(&Event{Type: EventPointerDown, X: x, Y: y, TouchID: touchID}) {
+				ct.HandleJustPressedTouchID(touchID, x, y)
+			}
+			ct.touchIDs = append(ct.touchIDs, touchID)
+		}
+	}
+
+	touchIDs := ct.touchIDs
+	for t := range touchIDs {
+		if input.IsTouchJustReleased(touchIDs[t]) {
+			pos := lastTouchPosition(touchIDs[t])
+			dispatch(&Event{Type: EventPointerUp, X: pos.X, Y: pos.Y, TouchID: touchIDs[t]})
+			ct.HandleJustReleasedTouchID(touchIDs[t], pos.X, pos.Y)
+		} else {
+			x, y := input.TouchPosition(touchIDs[t])
+			x, y = toLocal(toLogicalPoint(x, y, scale))
+			if pos := lastTouchPosition(touchIDs[t]); pos.X != x || pos.Y != y {
+				dispatch(&Event{Type: EventPointerMove, X: x, Y: y, TouchID: touchIDs[t]})
+			}
+			recordTouchPosition(touchIDs[t], x, y)
+		}
+	}
+}
+
+func (ct *containerEmbed) handleMouseEvents(scale float64, toLocal func(x, y int) (int, int), dispatch func(e *Event) bool) {
+	x, y := input.CursorPosition()
+	x, y = toLocal(toLogicalPoint(x, y, scale))
+	if ct.hasCursor && ct.cursor != image.Pt(x, y) {
+		dispatch(&Event{Type: EventPointerMove, X: x, Y: y, TouchID: -1})
+	}
+	ct.cursor, ct.hasCursor = image.Pt(x, y), true
+	ct.handleMouse(x, y)
+	if dx, dy := input.Wheel(); dx != 0 || dy != 0 {
+		if dispatch(&Event{Type: EventWheel, X: x, Y: y, TouchID: -1, WheelX: dx, WheelY: dy}) {
+			ct.handleWheel(x, y, dx, dy)
+		}
+	}
+	ct.handleMouseEnterLeave(x, y)
+	for b := MouseButtonLeft; b < mouseButtonCount; b++ {
+		if input.IsMouseButtonJustPressed(b) {
+			e := &Event{Type: EventPointerDown, X: x, Y: y, Button: b, TouchID: -1}
+			if dispatch(e) {
+				// A left press is hit-tested once for the left button, button and mouse button handlers.
+				if b == MouseButtonLeft {
+					ct.handleMouseButtonLeftPressed(x, y)
+				} else {
+					ct.handleMouseButtonPressed(b, x, y)
+				}
+			}
+		}
+		if input.IsMouseButtonJustReleased(b) {
+			dispatch(&Event{Type: EventPointerUp, X: x, Y: y, Button: b, TouchID: -1})
+			if b == MouseButtonLeft {
+				ct.handleMouseButtonLeftReleased(x, y)
+			}
+			ct.handleMouseButtonReleased(b, x, y)
+		}
+	}
+}
+
+func (ct *containerEmbed) setFrame(frame image.Rectangle) {
+	ct.frame = frame
+	ct.isDirty = true
+}
+
+func (ct *containerEmbed) childFrame(c *child) *image.Rectangle {
+	if !c.absolute {
+		r := c.bounds.Add(ct.frame.Min)
+		return &r
+	}
+	return &c.bounds
+}
+
+type touchPosition struct {
+	X, Y int
+}
+
+var (
+	touchPositions = make(map[TouchID]touchPosition)
+)
+
+func recordTouchPosition(t TouchID, x, y int) {
+	touchPositions[t] = touchPosition{x, y}
+}
+
+func lastTouchPosition(t TouchID) *touchPosition {
+	s, ok := touchPositions[t]
+	if ok {
+		return &s
+	}
+	return &touchPosition{0, 0}
+}
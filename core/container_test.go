@@ -0,0 +1,165 @@
+package core
+
+import (
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsInside(t *testing.T) {
+	for _, tt := range []struct {
+		r    image.Rectangle
+		x, y int
+		want bool
+	}{
+		{
+			r: image.Rect(0, 0, 10, 10),
+			x: 0, y: 0,
+			want: true,
+		},
+		{
+			r: image.Rect(10, 10, 10, 10),
+			x: 10, y: 10,
+			want: true,
+		},
+		{
+			r: image.Rect(10, 10, 20, 20),
+			x: 20, y: 20,
+			want: true,
+		},
+	} {
+		require.Equal(t, tt.want, isInside(&tt.r, tt.x, tt.y))
+	}
+}
+
+type mouseButtonHandler struct {
+	pressed  []MouseButton
+	released []MouseButton
+	isCancel bool
+}
+
+func (h *mouseButtonHandler) HandleJustPressedMouseButton(b MouseButton, x, y int) bool {
+	h.pressed = append(h.pressed, b)
+	return b != MouseButtonMiddle
+}
+
+func (h *mouseButtonHandler) HandleJustReleasedMouseButton(b MouseButton, x, y int, isCancel bool) {
+	h.released = append(h.released, b)
+	h.isCancel = isCancel
+}
+
+func TestMouseButtonHandler(t *testing.T) {
+	back := &mouseButtonHandler{}
+	front := &mouseButtonHandler{}
+	root := &View{Width: 100, Height: 100}
+	root.AddChild(&View{Position: PositionAbsolute, Width: 50, Height: 50, Handler: back})
+	root.AddChild(&View{Position: PositionAbsolute, Left: 20, Top: 20, Width: 50, Height: 50, Handler: front})
+	root.Update()
+
+	require.True(t, root.handleMouseButtonPressed(MouseButtonRight, 30, 30))
+	require.Equal(t, []MouseButton{MouseButtonRight}, front.pressed)
+	require.Empty(t, back.pressed, "only the topmost view handles the press")
+
+	require.False(t, root.handleMouseButtonPressed(MouseButtonMiddle, 30, 30))
+	require.Equal(t, []MouseButton{MouseButtonMiddle}, back.pressed, "an unhandled press falls through")
+
+	root.handleMouseButtonReleased(MouseButtonMiddle, 30, 30)
+	require.Empty(t, front.released, "the middle button was not handled by the front view")
+
+	root.handleMouseButtonReleased(MouseButtonRight, 90, 90)
+	require.Equal(t, []MouseButton{MouseButtonRight}, front.released)
+	require.True(t, front.isCancel)
+	root.handleMouseButtonReleased(MouseButtonRight, 90, 90)
+	require.Len(t, front.released, 1, "a release is handled once")
+
+	// The pressed view reports Pressed, and a release at an unknown position cancels it.
+	require.True(t, root.handleMouseButtonPressed(MouseButtonRight, 10, 10))
+	backChild := root.children[0]
+	require.True(t, backChild.item.newDrawContext(&Recorder{}, backChild.item.frame, backChild).Pressed)
+	root.handleMouseButtonReleased(MouseButtonRight, 0, 0)
+	require.Equal(t, []MouseButton{MouseButtonRight}, back.released)
+	require.True(t, back.isCancel, "the release at (0, 0) is inside the view but unknown")
+	require.False(t, backChild.item.newDrawContext(&Recorder{}, backChild.item.frame, backChild).Pressed)
+}
+
+// buttonMouseButtonHandler is a button that also handles the mouse buttons.
+type buttonMouseButtonHandler struct {
+	mockHandler
+	mouseButtonHandler
+}
+
+func TestMouseButtonHandlerUnderButton(t *testing.T) {
+	in := useFakeInput(t)
+	back := &mouseButtonHandler{}
+	button := &mockHandler{}
+	root := &View{Width: 100, Height: 100}
+	root.AddChild(&View{Position: PositionAbsolute, Width: 50, Height: 50, Handler: back})
+	root.AddChild(&View{Position: PositionAbsolute, Left: 20, Top: 20, Width: 20, Height: 20, Handler: button})
+	both := &buttonMouseButtonHandler{}
+	root.AddChild(&View{Position: PositionAbsolute, Left: 60, Top: 60, Width: 20, Height: 20, Handler: both})
+	root.Update()
+
+	in.x, in.y = 30, 30
+	in.justPressed[MouseButtonLeft] = true
+	root.Update()
+	require.True(t, button.IsPressed)
+	require.Empty(t, back.pressed, "the left press is handled once by the topmost view")
+
+	in.reset()
+	in.justReleased[MouseButtonLeft] = true
+	root.Update()
+	require.True(t, button.IsReleased)
+	require.Empty(t, back.released)
+
+	in.reset()
+	in.x, in.y = 70, 70
+	in.justPressed[MouseButtonLeft] = true
+	root.Update()
+	require.True(t, both.IsPressed)
+	require.Empty(t, both.pressed, "a view that is a button gets the left press once")
+
+	in.reset()
+	in.x, in.y = 10, 10
+	in.justPressed[MouseButtonLeft] = true
+	root.Update()
+	require.Equal(t, []MouseButton{MouseButtonLeft}, back.pressed)
+}
+
+type wheelHandler struct {
+	handles bool
+	dy      []float64
+}
+
+func (h *wheelHandler) HandleWheel(x, y int, dx, dy float64) bool {
+	h.dy = append(h.dy, dy)
+	return h.handles
+}
+
+func TestWheelHandler(t *testing.T) {
+	under := &wheelHandler{handles: true}
+	list := &wheelHandler{handles: true}
+	item := &wheelHandler{}
+	root := &View{Width: 100, Height: 100}
+	root.AddChild(&View{Position: PositionAbsolute, Width: 100, Height: 100, Handler: under})
+	listView := &View{Position: PositionAbsolute, Width: 100, Height: 100, Handler: list}
+	listView.AddChild(&View{Position: PositionAbsolute, Left: 10, Top: 10, Width: 20, Height: 20, Handler: item})
+	root.AddChild(listView)
+	root.Update()
+
+	handled, _ := root.handleWheel(15, 15, 0, -1)
+	require.True(t, handled)
+	require.Equal(t, []float64{-1}, item.dy, "the topmost view receives the scroll first")
+	require.Equal(t, []float64{-1}, list.dy, "the unhandled scroll bubbles to the ancestors")
+
+	handled, _ = root.handleWheel(50, 50, 0, 2)
+	require.True(t, handled)
+	require.Len(t, item.dy, 1)
+	require.Equal(t, []float64{-1, 2}, list.dy)
+
+	list.handles = false
+	handled, hit := root.handleWheel(50, 50, 0, 3)
+	require.False(t, handled)
+	require.True(t, hit)
+	require.Empty(t, under.dy, "views under the topmost view do not receive the scroll")
+}
@@ -0,0 +1,61 @@
+package core
+
+import (
+	"image/color"
+)
+
+var (
+	// Debug draws the borders and the frames of the views when they are drawn.
+	Debug           = false
+	debugFlag       = &Debug
+	debugColor      = color.RGBA{0xff, 0, 0, 0xff}
+	debugColorShift = ColorM{}
+)
+
+// SetDebugFlag makes the views read the debug flag from flag instead of Debug.
+// The engine adapter sets it to its own Debug variable.
+func SetDebugFlag(flag *bool) {
+	debugFlag = flag
+}
+
+func isDebug() bool {
+	return *debugFlag
+}
+
+// debugBorders strokes the frames of the view tree level by level. The strokes are
+// drawn in a row so that a renderer that batches rectangles draws them at once.
+func debugBorders(r Renderer, root containerEmbed, scale float64) {
+	queue := []containerEmbed{}
+	queue = append(queue, root)
+	renderColor := resetDebugColor()
+
+	for len(queue) > 0 {
+		levelSize := len(queue)
+		for levelSize != 0 {
+			curr := queue[0]
+			queue = queue[1:]
+
+			r.StrokeRect(toPhysicalRect(curr.frame, scale), 0, 2, renderColor)
+
+			for _, c := range curr.children {
+				if c.item.Display == DisplayNone {
+					continue
+				}
+				queue = append(queue, c.item.containerEmbed)
+			}
+			levelSize--
+		}
+
+		renderColor = rotateDebugColor()
+	}
+}
+
+func rotateDebugColor() color.Color {
+	debugColorShift.RotateHue(1.66)
+	return debugColorShift.Apply(debugColor)
+}
+
+func resetDebugColor() color.Color {
+	debugColorShift = ColorM{}
+	return debugColor
+}
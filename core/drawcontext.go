@@ -0,0 +1,108 @@
+package core
+
+import (
+	"image"
+	"math"
+	"time"
+)
+
+// DrawContext is the information passed to a ContextDrawer.
+// Rectangles are in screen pixels on Screen, before the transforms are applied.
+type DrawContext struct {
+	// Screen is the target of the renderer. It is nil if the renderer has no target.
+	Screen Image
+	// Renderer is the renderer of the view tree. Drawing with it rather than
+	// on Screen is captured by a Recorder.
+	Renderer Renderer
+	View     *View
+	// Frame is the border box of the view.
+	Frame image.Rectangle
+	// Content is the content box of the view: the frame inset by the border.
+	Content image.Rectangle
+	// Clip is the area of Screen that is visible. Drawing outside of it is discarded.
+	Clip image.Rectangle
+	// GeoM is the accumulated transform of the view and its ancestors
+	// from Screen to the window.
+	GeoM GeoM
+	// Alpha is the accumulated opacity of the view and its ancestors.
+	Alpha float64
+	// Scale is the UI scale of the view tree.
+	Scale float64
+	// DeltaTime is the time elapsed since the previous frame was drawn.
+	DeltaTime time.Duration
+	// Hovered is true if the mouse cursor is over the view.
+	Hovered bool
+	// Pressed is true if the view is being pressed by a touch or a button of the mouse.
+	Pressed bool
+}
+
+// ContextDrawer represents a component that draws with a DrawContext.
+// It takes precedence over Drawer and DrawHandler.
+type ContextDrawer interface {
+	// DrawWithContext draws the content of the component.
+	DrawWithContext(ctx *DrawContext)
+}
+
+// newDrawContext returns the context to draw the view in frame on screen.
+// c is the child entry of the view in its parent, or nil for the root view.
+func (v *View) newDrawContext(r Renderer, frame image.Rectangle, c *child) *DrawContext {
+	scale := v.UIScale()
+	border := int(math.Round(float64(v.BorderWidth) * scale))
+	screen := r.Target()
+	ctx := &DrawContext{
+		Screen:    screen,
+		Renderer:  r,
+		View:      v,
+		Frame:     frame,
+		Content:   frame.Inset(border),
+		Alpha:     1,
+		Scale:     scale,
+		DeltaTime: v.root().deltaTime,
+	}
+	if screen != nil {
+		ctx.Clip = screen.Bounds()
+	}
+	for p := v; p != nil; p = p.parent {
+		ctx.Alpha *= p.opacity()
+	}
+	ctx.GeoM, _ = v.screenGeoM()
+	if o, ok := r.(*offsetRenderer); ok {
+		// The view is drawn to the image of a cache: the context is in the pixels of the image.
+		ctx.Renderer = o.Renderer
+		ctx.Frame = ctx.Frame.Sub(o.origin)
+		ctx.Content = ctx.Content.Sub(o.origin)
+		g := o.toScreen()
+		g.Concat(ctx.GeoM)
+		ctx.GeoM = g
+	}
+	if c != nil {
+		ctx.Hovered = c.isHovered
+		ctx.Pressed = c.isButtonPressed || c.isMouseLeftButtonHandler || c.isMouseButtonPressed()
+	}
+	return ctx
+}
+
+// drawHandler draws the handler of the view.
+func (v *View) drawHandler(ctx *DrawContext) {
+	switch h := v.Handler.(type) {
+	case ContextDrawer:
+		h.DrawWithContext(ctx)
+	case DrawHandler:
+		h.HandleDraw(ctx.Screen, ctx.Frame)
+	case Drawer:
+		h.Draw(ctx.Screen, ctx.Frame, v)
+	default:
+		if handlerAdapter != nil && h != nil {
+			handlerAdapter.Draw(h, ctx)
+		}
+	}
+}
+
+// updateDeltaTime records the time elapsed since the previous call.
+func (v *View) updateDeltaTime() {
+	now := time.Now()
+	if !v.lastDraw.IsZero() {
+		v.deltaTime = now.Sub(v.lastDraw)
+	}
+	v.lastDraw = now
+}
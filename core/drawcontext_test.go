@@ -0,0 +1,76 @@
+package core
+
+import (
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type contextDrawer struct {
+	ctx DrawContext
+}
+
+func (d *contextDrawer) DrawWithContext(ctx *DrawContext) {
+	d.ctx = *ctx
+}
+
+func (d *contextDrawer) HandlePress(x, y int, t TouchID) {}
+
+func (d *contextDrawer) HandleRelease(x, y int, isCancel bool) {}
+
+func TestDrawContext(t *testing.T) {
+	d := &contextDrawer{}
+	root := &View{Width: 100, Height: 100}
+	panel := &View{Width: 100, Height: 100, Opacity: Float(.5), Cache: true}
+	button := &View{Width: 40, Height: 20, BorderWidth: 2, Handler: d}
+	panel.AddChild(button)
+	root.AddChild(panel)
+	root.SetUIScale(2)
+
+	screen := image.NewRGBA(image.Rect(0, 0, 200, 200))
+	root.Draw(screen)
+	ctx := d.ctx
+	require.Same(t, button, ctx.View)
+	require.Equal(t, image.Rect(0, 0, 80, 40), ctx.Frame)
+	require.Equal(t, image.Rect(4, 4, 76, 36), ctx.Content)
+	require.Equal(t, image.Rect(0, 0, 200, 200), ctx.Clip)
+	require.Equal(t, .5, ctx.Alpha)
+	require.Equal(t, 2., ctx.Scale)
+	require.False(t, ctx.Hovered)
+	require.False(t, ctx.Pressed)
+
+	root.handleMouseEnterLeave(10, 5)
+	root.handleMouseButtonLeftPressed(10, 5)
+	panel.SetTransform(&Transform{TranslateX: 5})
+	button.Invalidate()
+	root.Draw(screen)
+	ctx = d.ctx
+	require.True(t, ctx.Hovered)
+	require.True(t, ctx.Pressed)
+	x, y := ctx.GeoM.Apply(0, 0)
+	require.Equal(t, [2]float64{10, 0}, [2]float64{x, y})
+
+	root.handleMouseButtonLeftReleased(10, 5)
+	root.handleMouseEnterLeave(0, 90)
+	button.Invalidate()
+	root.Draw(screen)
+	require.False(t, d.ctx.Hovered)
+	require.False(t, d.ctx.Pressed)
+}
+
+func TestHandlerDrawWithContext(t *testing.T) {
+	var frame image.Rectangle
+	h := NewHandler(HandlerOpts{
+		Draw: func(screen Image, f image.Rectangle, v *View) { frame = f },
+	})
+	(&View{Width: 10, Height: 10}).AddChild(&View{Width: 10, Height: 10, Handler: h}).Draw(nil)
+	require.Equal(t, image.Rect(0, 0, 10, 10), frame)
+
+	var ctx *DrawContext
+	h = NewHandler(HandlerOpts{
+		DrawWithContext: func(c *DrawContext) { ctx = c },
+	})
+	(&View{Width: 10, Height: 10}).AddChild(&View{Width: 10, Height: 10, Handler: h}).Draw(nil)
+	require.Equal(t, image.Rect(0, 0, 10, 10), ctx.Frame)
+}
@@ -0,0 +1,119 @@
+package core
+
+import "math"
+
+// Easing maps the linear progress of an animation from 0 to 1 to the eased progress.
+// The eased progress can leave [0, 1] to overshoot, e.g. with EaseOutBack.
+type Easing func(t float64) float64
+
+// Linear is the easing of a constant speed. It is used when an easing is nil.
+func Linear(t float64) float64 { return t }
+
+// EaseIn starts slowly and speeds up.
+func EaseIn(t float64) float64 { return t * t }
+
+// EaseOut starts fast and slows down.
+func EaseOut(t float64) float64 { return t * (2 - t) }
+
+// EaseInOut starts and ends slowly.
+func EaseInOut(t float64) float64 {
+	if t < 0.5 {
+		return 2 * t * t
+	}
+	return -1 + (4-2*t)*t
+}
+
+// EaseInCubic is a stronger EaseIn.
+func EaseInCubic(t float64) float64 { return t * t * t }
+
+// EaseOutCubic is a stronger EaseOut.
+func EaseOutCubic(t float64) float64 {
+	t--
+	return t*t*t + 1
+}
+
+// EaseInOutCubic is a stronger EaseInOut.
+func EaseInOutCubic(t float64) float64 {
+	if t < 0.5 {
+		return 4 * t * t * t
+	}
+	t = 2*t - 2
+	return t*t*t/2 + 1
+}
+
+// EaseOutBack overshoots the end and settles back.
+func EaseOutBack(t float64) float64 {
+	const c1 = 1.70158
+	const c3 = c1 + 1
+	t--
+	return 1 + c3*t*t*t + c1*t*t
+}
+
+// EaseOutElastic oscillates around the end before it settles.
+func EaseOutElastic(t float64) float64 {
+	if t <= 0 || t >= 1 {
+		return t
+	}
+	return math.Pow(2, -10*t)*math.Sin((t*10-0.75)*2*math.Pi/3) + 1
+}
+
+// EaseOutBounce bounces on the end like a dropped ball.
+func EaseOutBounce(t float64) float64 {
+	const n1 = 7.5625
+	const d1 = 2.75
+	switch {
+	case t < 1/d1:
+		return n1 * t * t
+	case t < 2/d1:
+		t -= 1.5 / d1
+		return n1*t*t + 0.75
+	case t < 2.5/d1:
+		t -= 2.25 / d1
+		return n1*t*t + 0.9375
+	default:
+		t -= 2.625 / d1
+		return n1*t*t + 0.984375
+	}
+}
+
+// CubicBezier returns the easing of a cubic Bézier curve from (0, 0) to (1, 1)
+// with the control points (x1, y1) and (x2, y2), like cubic-bezier() in CSS.
+// x1 and x2 must be in [0, 1].
+func CubicBezier(x1, y1, x2, y2 float64) Easing {
+	// bezier returns a coordinate of the curve at s for the control coordinates a and b.
+	bezier := func(s, a, b float64) float64 {
+		return ((1-3*b+3*a)*s+(3*b-6*a))*s*s + 3*a*s
+	}
+	slope := func(s, a, b float64) float64 {
+		return 3*(1-3*b+3*a)*s*s + 2*(3*b-6*a)*s + 3*a
+	}
+	return func(t float64) float64 {
+		if t <= 0 || t >= 1 {
+			return t
+		}
+		// Find s where x(s) = t with Newton's method, then bisection if it does not converge.
+		s := t
+		for i := 0; i < 8; i++ {
+			x := bezier(s, x1, x2) - t
+			if math.Abs(x) < 1e-7 {
+				return bezier(s, y1, y2)
+			}
+			d := slope(s, x1, x2)
+			if math.Abs(d) < 1e-7 {
+				break
+			}
+			s -= x / d
+		}
+		lo, hi := 0.0, 1.0
+		s = t
+		for i := 0; i < 64 && hi-lo > 1e-7; i++ {
+			if bezier(s, x1, x2) < t {
+				lo = s
+			} else {
+				hi = s
+			}
+			s = (lo + hi) / 2
+		}
+		return bezier(s, y1, y2)
+	}
+}
@@ -0,0 +1,40 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEasing(t *testing.T) {
+	easings := map[string]Easing{
+		"Linear":         Linear,
+		"EaseIn":         EaseIn,
+		"EaseOut":        EaseOut,
+		"EaseInOut":      EaseInOut,
+		"EaseInCubic":    EaseInCubic,
+		"EaseOutCubic":   EaseOutCubic,
+		"EaseInOutCubic": EaseInOutCubic,
+		"EaseOutBack":    EaseOutBack,
+		"EaseOutElastic": EaseOutElastic,
+		"EaseOutBounce":  EaseOutBounce,
+	}
+	for name, e := range easings {
+		t.Run(name, func(t *testing.T) {
+			require.InDelta(t, 0, e(0), 1e-9)
+			require.InDelta(t, 1, e(1), 1e-9)
+		})
+	}
+	require.Equal(t, 0.25, EaseIn(0.5))
+	require.Equal(t, 0.75, EaseOut(0.5))
+	require.Equal(t, 0.5, EaseInOut(0.5))
+	require.Greater(t, EaseOutBack(0.8), 1.0)
+}
+
+func TestCubicBezier(t *testing.T) {
+	ease := CubicBezier(0.42, 0, 0.58, 1)
+	require.InDelta(t, 0.5, ease(0.5), 1e-6)
+	require.Less(t, ease(0.25), 0.25)
+	require.Greater(t, ease(0.75), 0.75)
+	require.InDelta(t, 0.3, CubicBezier(0, 0, 1, 1)(0.3), 1e-6)
+}
@@ -0,0 +1,124 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// hasEffect returns true if the view needs to be composited from an offscreen image
+// to apply its opacity, color matrix or transform to the whole subtree.
+func (v *View) hasEffect() bool {
+	if v.Hidden || v.Display == DisplayNone {
+		return false
+	}
+	return v.opacity() < 1 || v.ColorM != nil || v.hasTransform()
+}
+
+func (v *View) opacity() float64 {
+	if v.Opacity == nil {
+		return 1
+	}
+	return *v.Opacity
+}
+
+// beginEffect begins a layer that the view and its descendants are drawn to
+// if the view has an effect. It returns true if it began a layer.
+func (v *View) beginEffect(r Renderer) bool {
+	if !v.hasEffect() {
+		return false
+	}
+	r.BeginLayer()
+	return true
+}
+
+// endEffect composites the layer begun by beginEffect with the effect of the view.
+func (v *View) endEffect(r Renderer, began bool) {
+	if !began {
+		return
+	}
+	opts := &LayerOptions{Alpha: v.opacity()}
+	if v.ColorM != nil {
+		opts.ColorM = *v.ColorM
+	}
+	if v.hasTransform() {
+		scale := v.UIScale()
+		opts.GeoM = v.geoM(toPhysicalRect(v.frame, scale), scale)
+		opts.Filter = FilterLinear
+	}
+	r.EndLayer(opts)
+}
+
+// parseFilter parses the CSS filter property such as `grayscale(100%) brightness(0.8)`
+// into a color matrix. It supports grayscale, saturate, brightness, contrast, invert
+// and hue-rotate.
+func parseFilter(val string) (any, error) {
+	if val == "none" {
+		return (*ColorM)(nil), nil
+	}
+	m := &ColorM{}
+	for _, f := range splitValues(val, ' ') {
+		open := strings.Index(f, "(")
+		if open < 0 || !strings.HasSuffix(f, ")") {
+			return nil, fmt.Errorf("invalid filter: %s", f)
+		}
+		name, arg := f[:open], strings.TrimSpace(f[open+1:len(f)-1])
+		if name == "hue-rotate" {
+			deg, err := parseAngle(arg)
+			if err != nil {
+				return nil, err
+			}
+			m.RotateHue(deg * math.Pi / 180)
+			continue
+		}
+		amount := 1.
+		if arg != "" {
+			a, err := parseFraction(arg)
+			if err != nil {
+				return nil, fmt.Errorf("invalid filter: %s", f)
+			}
+			amount = a.(float64)
+		}
+		switch name {
+		case "grayscale":
+			m.ChangeHSV(0, 1-math.Min(amount, 1), 1)
+		case "saturate":
+			m.ChangeHSV(0, amount, 1)
+		case "brightness":
+			m.Scale(amount, amount, amount, 1)
+		case "contrast":
+			m.Scale(amount, amount, amount, 1)
+			m.Translate((1-amount)/2, (1-amount)/2, (1-amount)/2, 0)
+		case "invert":
+			amount = math.Min(amount, 1)
+			m.Scale(1-2*amount, 1-2*amount, 1-2*amount, 1)
+			m.Translate(amount, amount, amount, 0)
+		default:
+			return nil, fmt.Errorf("unknown filter: %s", name)
+		}
+	}
+	return m, nil
+}
+
+// parseAngle parses an angle such as `90deg`, `0.5turn` or `1.57rad` into degrees.
+func parseAngle(val string) (float64, error) {
+	units := []struct {
+		suffix string
+		deg    float64
+	}{{"deg", 1}, {"turn", 360}, {"rad", 180 / math.Pi}}
+	for _, u := range units {
+		if strings.HasSuffix(val, u.suffix) {
+			f, err := strconv.ParseFloat(strings.TrimSuffix(val, u.suffix), 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid angle: %s", val)
+			}
+			return f * u.deg, nil
+		}
+	}
+	f, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid angle: %s", val)
+	}
+	return f, nil
+}
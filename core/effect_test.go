@@ -0,0 +1,81 @@
+package core
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFilter(t *testing.T) {
+	apply := func(filter string, c color.Color) color.RGBA {
+		t.Helper()
+		m, err := parseFilter(filter)
+		require.NoError(t, err)
+		return color.RGBAModel.Convert(m.(*ColorM).Apply(c)).(color.RGBA)
+	}
+
+	gray := apply("grayscale(100%)", color.RGBA{0xff, 0, 0, 0xff})
+	require.Equal(t, gray.R, gray.G)
+	require.Equal(t, gray.G, gray.B)
+
+	require.Equal(t, color.RGBA{0x7f, 0x7f, 0x7f, 0xff}, apply("brightness(0.5)", color.White))
+	require.Equal(t, color.RGBA{0, 0, 0, 0xff}, apply("invert(1)", color.White))
+	require.Equal(t, color.RGBA{0x7f, 0x7f, 0x7f, 0xff}, apply("grayscale() brightness(50%)", color.White))
+
+	m, err := parseFilter("none")
+	require.NoError(t, err)
+	require.Nil(t, m)
+
+	for _, val := range []string{"blur(2px)", "grayscale", "grayscale(abc)", "hue-rotate(1foo)"} {
+		_, err := parseFilter(val)
+		require.Error(t, err, val)
+	}
+}
+
+func TestParseAngle(t *testing.T) {
+	for val, want := range map[string]float64{
+		"90deg":   90,
+		"0.5turn": 180,
+		"45":      45,
+	} {
+		got, err := parseAngle(val)
+		require.NoError(t, err)
+		require.InDelta(t, want, got, 1e-9, val)
+	}
+}
+
+func TestSubtreeEffect(t *testing.T) {
+	resetComponents()
+	v := Parse(`
+		<view style="width: 100; height: 100">
+			<view id="dialog" style="opacity: 0.5; filter: grayscale(1)">
+				<view id="child" style="width: 10; height: 10; background-color: red"></view>
+			</view>
+			<view id="plain"></view>
+		</view>`, nil)
+
+	dialog := v.MustGetByID("dialog")
+	require.Equal(t, .5, *dialog.Opacity)
+	require.NotNil(t, dialog.ColorM)
+	require.True(t, dialog.hasEffect())
+	require.False(t, v.MustGetByID("child").hasEffect())
+	require.False(t, v.MustGetByID("plain").hasEffect())
+
+	r := &Recorder{}
+	require.False(t, v.beginEffect(r))
+	require.Empty(t, r.Commands)
+
+	require.True(t, dialog.beginEffect(r))
+	dialog.endEffect(r, true)
+	require.Equal(t, []DrawOp{DrawBeginLayer, DrawEndLayer}, r.Ops())
+	require.Equal(t, .5, r.Commands[1].Layer.Alpha)
+	require.False(t, r.Commands[1].Layer.ColorM.IsIdentity())
+
+	screen := image.NewRGBA(image.Rect(0, 0, 100, 100))
+	v.Draw(screen)
+
+	dialog.SetHidden(true)
+	require.False(t, dialog.hasEffect())
+}
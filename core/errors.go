@@ -0,0 +1,37 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+type ErrorList struct {
+	errors []error
+}
+
+func (e *ErrorList) Add(err error) {
+	if err != nil {
+		e.errors = append(e.errors, err)
+	}
+}
+
+func (e *ErrorList) Error() string {
+	errStrings := make([]string, len(e.errors))
+	for i, err := range e.errors {
+		errStrings[i] = err.Error()
+	}
+	return strings.Join(errStrings, "; ")
+}
+
+func (e *ErrorList) HasErrors() bool {
+	return len(e.errors) > 0
+}
+
+// warn prints the problems of a parsed document, such as an invalid style,
+// that are skipped instead of stopping the parsing. Tests replace it to check them.
+var warn = func(msg string) { println(msg) }
+
+// warnf formats and prints a problem of a parsed document with warn.
+func warnf(format string, args ...any) {
+	warn(fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,253 @@
+package core
+
+import (
+	"image"
+	"math"
+)
+
+// EventType is the type of an Event.
+type EventType int
+
+const (
+	// EventPointerDown happens when a mouse button or a touch is just pressed.
+	EventPointerDown EventType = iota
+	// EventPointerUp happens when a mouse button or a touch is just released.
+	EventPointerUp
+	// EventPointerMove happens when the mouse cursor or a touch moves.
+	EventPointerMove
+	// EventKeyDown happens when a key is just pressed.
+	EventKeyDown
+	// EventKeyUp happens when a key is just released.
+	EventKeyUp
+	// EventWheel happens when the mouse wheel scrolls.
+	EventWheel
+)
+
+var eventTypeNames = [...]string{"pointerdown", "pointerup", "pointermove", "keydown", "keyup", "wheel"}
+
+func (t EventType) String() string {
+	if t < 0 || int(t) >= len(eventTypeNames) {
+		return "unknown"
+	}
+	return eventTypeNames[t]
+}
+
+// EventPhase is the phase of the dispatch of an Event.
+type EventPhase int
+
+const (
+	// EventPhaseNone is the phase of an event that is not being dispatched.
+	EventPhaseNone EventPhase = iota
+	// EventPhaseCapturing is the phase from the root view down to the parent of the target.
+	EventPhaseCapturing
+	// EventPhaseAtTarget is the phase of the target.
+	EventPhaseAtTarget
+	// EventPhaseBubbling is the phase from the parent of the target up to the root view.
+	EventPhaseBubbling
+)
+
+// Event is an input event dispatched to a view and its ancestors like a DOM event.
+// Pointer and wheel events target the topmost view under the pointer,
+// and key events target the root view.
+type Event struct {
+	Type EventType
+	// Target is the view that the event is dispatched to.
+	Target *View
+	// CurrentTarget is the view whose listeners are being called.
+	CurrentTarget *View
+	Phase         EventPhase
+	// X and Y are the position of a pointer or wheel event
+	// in the untransformed coordinate space of the current target.
+	X, Y int
+	// Button is the mouse button of a pointer down or up event of the mouse.
+	Button MouseButton
+	// TouchID is the touch of a pointer event, or -1 for the mouse.
+	TouchID TouchID
+	// Key is the key of a key event.
+	Key Key
+	// WheelX and WheelY are the scroll of a wheel event.
+	WheelX, WheelY float64
+
+	stopped   bool
+	prevented bool
+}
+
+// StopPropagation stops the dispatch of the event after the listeners of the current target.
+func (e *Event) StopPropagation() {
+	e.stopped = true
+}
+
+// PropagationStopped returns true if StopPropagation has been called.
+func (e *Event) PropagationStopped() bool {
+	return e.stopped
+}
+
+// PreventDefault prevents the default handling of the event. A prevented pointer down
+// or wheel event is not handled by the handler interfaces such as ButtonHandler and WheelHandler.
+func (e *Event) PreventDefault() {
+	e.prevented = true
+}
+
+// DefaultPrevented returns true if PreventDefault has been called.
+func (e *Event) DefaultPrevented() bool {
+	return e.prevented
+}
+
+// EventListener is a function that listens to the events of a view.
+type EventListener func(e *Event)
+
+// EventHandler represents a component that handles the events of its view
+// in the target and the bubbling phases, after the listeners of the view.
+type EventHandler interface {
+	HandleEvent(e *Event)
+}
+
+type eventListener struct {
+	typ     EventType
+	capture bool
+	f       EventListener
+}
+
+// AddEventListener adds a listener of the events of the type in the target and the bubbling phases.
+// It returns a function that removes the listener.
+func (v *View) AddEventListener(t EventType, l EventListener) (remove func()) {
+	return v.addEventListener(t, false, l)
+}
+
+// AddCaptureEventListener adds a listener of the events of the type in the capturing
+// and the target phases, so that the view sees the events before its descendants.
+// It returns a function that removes the listener.
+func (v *View) AddCaptureEventListener(t EventType, l EventListener) (remove func()) {
+	return v.addEventListener(t, true, l)
+}
+
+func (v *View) addEventListener(t EventType, capture bool, f EventListener) func() {
+	l := &eventListener{typ: t, capture: capture, f: f}
+	v.listeners = append(v.listeners, l)
+	return func() {
+		for i, ll := range v.listeners {
+			if ll == l {
+				v.listeners = append(v.listeners[:i:i], v.listeners[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// DispatchEvent dispatches the event to the view and its ancestors.
+// The position of the event is in the untransformed coordinate space of the view.
+// It returns false if the default has been prevented.
+func (v *View) DispatchEvent(e *Event) bool {
+	var path []*View
+	for p := v; p != nil; p = p.parent {
+		path = append([]*View{p}, path...)
+	}
+	points := make([]image.Point, len(path))
+	x, y := float64(e.X), float64(e.Y)
+	for i := len(path) - 1; i >= 0; i-- {
+		points[i] = image.Pt(int(math.Round(x)), int(math.Round(y)))
+		if p := path[i]; p.hasTransform() {
+			g := p.geoM(p.frame, 1)
+			x, y = g.Apply(x, y)
+		}
+	}
+	return dispatchEvent(e, path, points)
+}
+
+// dispatchEvent dispatches the event along the path from the root view to the target.
+// The points are the position of the event in the coordinate space of each view.
+func dispatchEvent(e *Event, path []*View, points []image.Point) bool {
+	target := len(path) - 1
+	e.Target = path[target]
+	e.stopped = false
+	at := func(i int, phase EventPhase) {
+		e.Phase = phase
+		e.CurrentTarget = path[i]
+		e.X, e.Y = points[i].X, points[i].Y
+	}
+	e.Phase = EventPhaseCapturing
+	for i := 0; i < target && !e.stopped; i++ {
+		at(i, EventPhaseCapturing)
+		path[i].callEventListeners(e, true)
+	}
+	if !e.stopped {
+		at(target, EventPhaseAtTarget)
+		path[target].callEventListeners(e, true)
+		path[target].callEventListeners(e, false)
+	}
+	for i := target - 1; i >= 0 && !e.stopped; i-- {
+		at(i, EventPhaseBubbling)
+		path[i].callEventListeners(e, false)
+	}
+	e.Phase = EventPhaseNone
+	e.CurrentTarget = nil
+	return !e.prevented
+}
+
+func (v *View) callEventListeners(e *Event, capture bool) {
+	// Listeners added or removed by a listener take effect from the next event.
+	for _, l := range v.listeners {
+		if l.typ == e.Type && l.capture == capture {
+			l.f(e)
+		}
+	}
+	if h, ok := v.Handler.(EventHandler); ok && !capture {
+		h.HandleEvent(e)
+	}
+}
+
+// hitTest returns the path from the view to the topmost view at the point,
+// and the point in the coordinate space of each view on the path.
+// The path has only the view if no view is at the point.
+func (v *View) hitTest(x, y int) ([]*View, []image.Point) {
+	path, points, ok := v.hit(x, y, nil, nil)
+	if !ok {
+		return []*View{v}, []image.Point{{x, y}}
+	}
+	return path, points
+}
+
+func (v *View) hit(x, y int, path []*View, points []image.Point) ([]*View, []image.Point, bool) {
+	path = append(path, v)
+	points = append(points, image.Pt(x, y))
+	for i := len(v.children) - 1; i >= 0; i-- {
+		c := v.children[i]
+		if c.item.Display == DisplayNone {
+			continue
+		}
+		cx, cy := c.toLocal(x, y)
+		if p, q, ok := c.item.hit(cx, cy, path, points); ok {
+			return p, q, true
+		}
+	}
+	if isInside(&v.frame, x, y) {
+		return path, points, true
+	}
+	return nil, nil, false
+}
+
+// dispatchPointerEvent dispatches a pointer or wheel event at its position
+// in the coordinate space of the root view.
+func (v *View) dispatchPointerEvent(e *Event) bool {
+	path, points := v.hitTest(e.X, e.Y)
+	return dispatchEvent(e, path, points)
+}
+
+// dispatchKeyEvents dispatches the key events of the frame to the root view.
+func (v *View) dispatchKeyEvents() {
+	for k := KeyArrowUp; k < keyCount; k++ {
+		if input.IsKeyJustPressed(k) {
+			v.keysDown[k] = true
+			v.DispatchEvent(&Event{Type: EventKeyDown, Key: k, TouchID: -1})
+		} else if v.keysDown[k] && !input.IsKeyPressed(k) {
+			v.keysDown[k] = false
+			v.DispatchEvent(&Event{Type: EventKeyUp, Key: k, TouchID: -1})
+		}
+	}
+}
+
+// processEvent dispatches the input events of the frame to the view tree.
+func (v *View) processEvent(scale float64, toLocal func(x, y int) (int, int)) {
+	v.dispatchKeyEvents()
+	v.containerEmbed.processEvent(scale, toLocal, v.dispatchPointerEvent)
+}
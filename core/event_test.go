@@ -0,0 +1,179 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeInput is an input whose state is set by the tests.
+// The just pressed and released states last one frame.
+type fakeInput struct {
+	noInput
+	x, y           int
+	wheelX         float64
+	wheelY         float64
+	justPressed    map[MouseButton]bool
+	justReleased   map[MouseButton]bool
+	keysPressed    map[Key]bool
+	keyJustPressed map[Key]bool
+}
+
+func useFakeInput(t *testing.T) *fakeInput {
+	in := &fakeInput{}
+	in.reset()
+	SetInput(in)
+	t.Cleanup(func() { SetInput(nil) })
+	return in
+}
+
+func (in *fakeInput) reset() {
+	in.wheelX, in.wheelY = 0, 0
+	in.justPressed = map[MouseButton]bool{}
+	in.justReleased = map[MouseButton]bool{}
+	in.keyJustPressed = map[Key]bool{}
+	if in.keysPressed == nil {
+		in.keysPressed = map[Key]bool{}
+	}
+}
+
+func (in *fakeInput) CursorPosition() (int, int)                   { return in.x, in.y }
+func (in *fakeInput) Wheel() (float64, float64)                    { return in.wheelX, in.wheelY }
+func (in *fakeInput) IsMouseButtonJustPressed(b MouseButton) bool  { return in.justPressed[b] }
+func (in *fakeInput) IsMouseButtonJustReleased(b MouseButton) bool { return in.justReleased[b] }
+func (in *fakeInput) IsKeyPressed(k Key) bool                      { return in.keysPressed[k] }
+func (in *fakeInput) IsKeyJustPressed(k Key) bool                  { return in.keyJustPressed[k] }
+
+func TestEventDispatch(t *testing.T) {
+	in := useFakeInput(t)
+	root := &View{Width: 100, Height: 100, ID: "root"}
+	panel := &View{Position: PositionAbsolute, Left: 10, Top: 10, Width: 50, Height: 50, ID: "panel"}
+	h := &mockHandler{}
+	button := &View{Position: PositionAbsolute, Left: 20, Top: 20, Width: 20, Height: 20, ID: "button", Handler: h}
+	panel.AddChild(button)
+	root.AddChild(panel)
+	root.Update()
+
+	var log []string
+	listen := func(v *View) {
+		v.AddCaptureEventListener(EventPointerDown, func(e *Event) {
+			log = append(log, fmt.Sprintf("capture %s %d %s", e.CurrentTarget.ID, e.Phase, e.Target.ID))
+		})
+		v.AddEventListener(EventPointerDown, func(e *Event) {
+			log = append(log, fmt.Sprintf("bubble %s %d (%d, %d)", e.CurrentTarget.ID, e.Phase, e.X, e.Y))
+		})
+	}
+	listen(root)
+	listen(panel)
+	listen(button)
+
+	in.x, in.y = 35, 35
+	in.justPressed[MouseButtonLeft] = true
+	root.Update()
+	require.Equal(t, []string{
+		"capture root 1 button",
+		"capture panel 1 button",
+		"capture button 2 button",
+		"bubble button 2 (35, 35)",
+		"bubble panel 3 (35, 35)",
+		"bubble root 3 (35, 35)",
+	}, log)
+	require.True(t, h.IsPressed)
+
+	// The panel intercepts the clicks of its descendants.
+	in.reset()
+	in.justReleased[MouseButtonLeft] = true
+	root.Update()
+	log = nil
+	h.Init()
+	remove := panel.AddCaptureEventListener(EventPointerDown, func(e *Event) {
+		e.StopPropagation()
+		e.PreventDefault()
+	})
+	in.reset()
+	in.justPressed[MouseButtonRight] = true
+	in.justPressed[MouseButtonLeft] = true
+	root.Update()
+	require.Equal(t, []string{
+		"capture root 1 button", "capture panel 1 button",
+		"capture root 1 button", "capture panel 1 button",
+	}, log)
+	require.False(t, h.IsPressed, "the default handling is prevented")
+
+	remove()
+	log = nil
+	in.reset()
+	in.x, in.y = 90, 5
+	in.justPressed[MouseButtonLeft] = true
+	root.Update()
+	require.Equal(t, []string{"capture root 2 root", "bubble root 2 (90, 5)"}, log)
+}
+
+func TestEventTypes(t *testing.T) {
+	in := useFakeInput(t)
+	root := &View{Width: 100, Height: 100}
+	box := &View{Width: 50, Height: 50}
+	root.AddChild(box)
+	in.x, in.y = 10, 10
+	root.Update()
+
+	var events []*Event
+	record := func(e *Event) {
+		c := *e
+		events = append(events, &c)
+	}
+	for _, typ := range []EventType{EventPointerMove, EventKeyDown, EventKeyUp, EventWheel} {
+		root.AddEventListener(typ, record)
+	}
+
+	in.keysPressed[KeySpace] = true
+	in.keyJustPressed[KeySpace] = true
+	root.Update()
+	require.Len(t, events, 1, "the cursor has not moved")
+	require.Equal(t, EventKeyDown, events[0].Type)
+	require.Equal(t, KeySpace, events[0].Key)
+	require.Equal(t, root, events[0].Target)
+
+	in.reset()
+	in.keysPressed[KeySpace] = false
+	in.x, in.y = 20, 10
+	in.wheelY = -1
+	root.Update()
+	require.Len(t, events, 4)
+	require.Equal(t, EventKeyUp, events[1].Type)
+	require.Equal(t, EventPointerMove, events[2].Type)
+	require.Equal(t, box, events[2].Target)
+	require.Equal(t, EventWheel, events[3].Type)
+	require.Equal(t, -1., events[3].WheelY)
+	require.Equal(t, "wheel", EventWheel.String())
+
+	// Letters and digits are dispatched like the other keys.
+	in.reset()
+	in.keysPressed[Key9] = true
+	in.keyJustPressed[Key9] = true
+	root.Update()
+	require.Len(t, events, 5)
+	require.Equal(t, Key9, events[4].Key)
+	in.reset()
+	in.keysPressed[Key9] = false
+	root.Update()
+	require.Equal(t, EventKeyUp, events[5].Type)
+	require.Equal(t, Key9, events[5].Key)
+}
+
+func TestDispatchEvent(t *testing.T) {
+	root := &View{Width: 100, Height: 100}
+	box := &View{Width: 50, Height: 50}
+	root.AddChild(box)
+	root.Update()
+	box.SetTransform(&Transform{TranslateX: 10})
+
+	var x int
+	root.AddEventListener(EventPointerDown, func(e *Event) {
+		x = e.X
+		e.PreventDefault()
+	})
+	require.False(t, box.DispatchEvent(&Event{Type: EventPointerDown, X: 5, Y: 5, TouchID: -1}))
+	require.Equal(t, 15, x, "the position is transformed to the coordinate space of the parent")
+}
@@ -1,5 +1,5 @@
 // Referenced code: https://github.com/golang/exp/blob/master/shiny/widget/flex/flex.go
-package furex
+package core
 
 import (
 	"fmt"
@@ -182,6 +182,8 @@ type Display uint8
 const (
 	DisplayFlex Display = iota
 	DisplayNone
+	// DisplayAnchor lays out the children with their anchors instead of flexbox.
+	DisplayAnchor
 )
 
 func (d Display) String() string {
@@ -190,6 +192,8 @@ func (d Display) String() string {
 		return "flex"
 	case DisplayNone:
 		return "none"
+	case DisplayAnchor:
+		return "anchor"
 	}
 	return fmt.Sprintf("unknown display: %d", d)
 }
@@ -200,7 +204,7 @@ type flexEmbed struct {
 
 // layout is the main routine that implements a subset of flexbox layout
 // https://www.w3.org/TR/css-flexbox-1/#layout-algorithm
-func (f *flexEmbed) layout(width, height int, container *containerEmbed) {
+func (f *flexEmbed) layout(width, height int, items []*LayoutItem) {
 	// 9.2. Line Length Determination
 	// Determine the available main and cross space for the flex items.
 	containerMainSize := float64(f.mainSize(width, height))
@@ -208,29 +212,8 @@ func (f *flexEmbed) layout(width, height int, container *containerEmbed) {
 
 	// Determine the flex base size and hypothetical main size of each item:
 	var children []element
-	for _, c := range container.children {
-		if c.item.Display == DisplayNone {
-			continue
-		}
-		if c.item.Position == PositionAbsolute {
-			x := container.frame.Min.X
-			if c.item.Left != 0 {
-				x = container.frame.Min.X + c.item.Left
-			} else if c.item.Right != nil {
-				x = container.frame.Max.X - *c.item.Right - c.item.Width
-			}
-			y := container.frame.Min.Y
-			if c.item.Top != 0 {
-				y = container.frame.Min.Y + c.item.Top
-			} else if c.item.Bottom != nil {
-				y = container.frame.Max.Y - *c.item.Bottom - c.item.Height
-			}
-			c.bounds = image.Rect(x, y, x+c.item.Width, y+c.item.Height)
-			c.item.frame = c.bounds
-			c.absolute = true
-			continue
-		}
-		c.absolute = false
+	for _, it := range items {
+		c := it.child
 		children = append(children, element{
 			widthInPct:   c.item.WidthInPct,
 			heightInPct:  c.item.HeightInPct,
@@ -675,19 +658,17 @@ func (f *flexEmbed) layout(width, height int, container *containerEmbed) {
 		for _, child := range line.child {
 			switch f.Direction {
 			case Row:
-				child.node.bounds = image.Rect(
+				child.node.setBounds(image.Rect(
 					round(child.mainOffset),
 					round(child.crossOffset),
 					round(child.mainOffset+child.mainSize),
-					round(child.crossOffset+child.crossSize))
-				child.node.item.setFrame(child.node.bounds.Add(f.frame.Min))
+					round(child.crossOffset+child.crossSize)), f.frame.Min)
 			case Column:
-				child.node.bounds = image.Rect(
+				child.node.setBounds(image.Rect(
 					round(child.crossOffset),
 					round(child.mainOffset),
 					round(child.crossOffset+child.crossSize),
-					round(child.mainOffset+child.mainSize))
-				child.node.item.setFrame(child.node.bounds.Add(f.frame.Min))
+					round(child.mainOffset+child.mainSize)), f.frame.Min)
 			default:
 				panic(fmt.Sprint("flex: bad direction ", f.Direction))
 			}
@@ -695,6 +676,28 @@ func (f *flexEmbed) layout(width, height int, container *containerEmbed) {
 	}
 }
 
+// layoutAbsolute places an absolutely positioned child inside the container.
+func layoutAbsolute(c *child, container *containerEmbed) {
+	x := container.frame.Min.X
+	if c.item.Left != 0 {
+		x = container.frame.Min.X + c.item.Left
+	} else if c.item.Right != nil {
+		x = container.frame.Max.X - *c.item.Right - c.item.Width
+	}
+	y := container.frame.Min.Y
+	if c.item.Top != 0 {
+		y = container.frame.Min.Y + c.item.Top
+	} else if c.item.Bottom != nil {
+		y = container.frame.Max.Y - *c.item.Bottom - c.item.Height
+	}
+	c.bounds = image.Rect(x, y, x+c.item.Width, y+c.item.Height)
+	if c.item.frame != c.bounds {
+		c.item.Invalidate()
+	}
+	c.item.frame = c.bounds
+	c.absolute = true
+}
+
 type element struct {
 	node                   *child
 	flexBaseSize           float64
@@ -0,0 +1,155 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/gobolditalic"
+	"golang.org/x/image/font/gofont/goitalic"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+)
+
+// DefaultFontFamily is the font family used when a view has no font family
+// or none of its families is registered. It is the Go Regular font.
+const DefaultFontFamily = "sans-serif"
+
+// DefaultFontSize is the font size used when a view and its ancestors have no font size.
+const DefaultFontSize = 16
+
+// FontVariant is the weight and the slant of a font in a family.
+type FontVariant uint8
+
+const (
+	FontRegular FontVariant = iota
+	FontBold
+	FontItalic
+	FontBoldItalic
+)
+
+func (f FontVariant) String() string {
+	switch f {
+	case FontRegular:
+		return "regular"
+	case FontBold:
+		return "bold"
+	case FontItalic:
+		return "italic"
+	case FontBoldItalic:
+		return "bold-italic"
+	}
+	return fmt.Sprintf("unknown font variant: %d", f)
+}
+
+// fontVariantOf returns the variant of a font with the weight and the slant.
+func fontVariantOf(bold, italic bool) FontVariant {
+	switch {
+	case bold && italic:
+		return FontBoldItalic
+	case bold:
+		return FontBold
+	case italic:
+		return FontItalic
+	}
+	return FontRegular
+}
+
+// fontKey is the key of a variant of a family in the registry.
+func fontKey(family string, variant FontVariant) string {
+	if variant == FontRegular {
+		return family
+	}
+	return family + ":" + variant.String()
+}
+
+type fontEntry struct {
+	font  *opentype.Font
+	face  font.Face
+	faces map[float64]font.Face
+}
+
+var registeredFonts = map[string]*fontEntry{}
+
+// RegisterFont registers a TrueType or OpenType font under the family name
+// so that it can be used with the font-family style.
+func RegisterFont(family string, data []byte) error {
+	f, err := opentype.Parse(data)
+	if err != nil {
+		return fmt.Errorf("furex: failed to parse font %s: %w", family, err)
+	}
+	registeredFonts[family] = &fontEntry{font: f, faces: map[float64]font.Face{}}
+	return nil
+}
+
+// RegisterFontVariant registers a TrueType or OpenType font as a variant of the family,
+// such as its bold face, for rich text spans. A family without the variant
+// uses its regular font instead.
+func RegisterFontVariant(family string, variant FontVariant, data []byte) error {
+	return RegisterFont(fontKey(family, variant), data)
+}
+
+// RegisterFontFace registers a font face of a fixed size, such as a bitmap font,
+// under the family name. The face is used for every font size.
+func RegisterFontFace(family string, face font.Face) {
+	registeredFonts[family] = &fontEntry{face: face}
+}
+
+// GetFontFace returns the face of the first registered family in the comma
+// separated list of families at the size in pixels. It falls back to the default font.
+func GetFontFace(families string, size float64) font.Face {
+	return getFontFace(families, size, FontRegular)
+}
+
+func getFontFace(families string, size float64, variant FontVariant) font.Face {
+	if _, ok := registeredFonts[DefaultFontFamily]; !ok {
+		registerDefaultFont()
+	}
+	for _, family := range strings.Split(families, ",") {
+		family = strings.Trim(strings.TrimSpace(family), `"'`)
+		if e, ok := registeredFonts[fontKey(family, variant)]; ok {
+			return e.faceOf(size)
+		}
+		if e, ok := registeredFonts[family]; ok {
+			return e.faceOf(size)
+		}
+	}
+	return registeredFonts[fontKey(DefaultFontFamily, variant)].faceOf(size)
+}
+
+func registerDefaultFont() {
+	for variant, data := range map[FontVariant][]byte{
+		FontRegular:    goregular.TTF,
+		FontBold:       gobold.TTF,
+		FontItalic:     goitalic.TTF,
+		FontBoldItalic: gobolditalic.TTF,
+	} {
+		if err := RegisterFontVariant(DefaultFontFamily, variant, data); err != nil {
+			panic(err)
+		}
+	}
+}
+
+func (e *fontEntry) faceOf(size float64) font.Face {
+	if e.face != nil {
+		return e.face
+	}
+	size = math.Round(size*4) / 4
+	if f, ok := e.faces[size]; ok {
+		return f
+	}
+	f, err := opentype.NewFace(e.font, &opentype.FaceOptions{
+		Size:    size,
+		DPI:     72,
+		Hinting: font.HintingFull,
+	})
+	if err != nil {
+		panic(fmt.Sprintf("furex: failed to create font face: %v", err))
+	}
+	e.faces[size] = f
+	return f
+}
+
+func resetFonts() { registeredFonts = map[string]*fontEntry{} }
@@ -0,0 +1,92 @@
+package core
+
+import "math"
+
+// GeoM is a 2D affine matrix. It has the same semantics as ebiten.GeoM:
+// the zero value is the identity and each operation is applied after the current matrix.
+type GeoM struct {
+	// The elements are stored minus the identity so that the zero value is the identity.
+	a1, b, c, d1, tx, ty float64
+}
+
+// Element returns the element of the matrix at (i, j), where i is the row (0 or 1)
+// and j is the column (0 to 2).
+func (g *GeoM) Element(i, j int) float64 {
+	switch {
+	case i == 0 && j == 0:
+		return g.a1 + 1
+	case i == 0 && j == 1:
+		return g.b
+	case i == 0 && j == 2:
+		return g.tx
+	case i == 1 && j == 0:
+		return g.c
+	case i == 1 && j == 1:
+		return g.d1 + 1
+	case i == 1 && j == 2:
+		return g.ty
+	}
+	panic("core: i or j is out of index")
+}
+
+// Apply transforms the point (x, y).
+func (g *GeoM) Apply(x, y float64) (float64, float64) {
+	return (g.a1+1)*x + g.b*y + g.tx, g.c*x + (g.d1+1)*y + g.ty
+}
+
+// Concat multiplies the matrix by other on the left, so that other is applied after it.
+func (g *GeoM) Concat(other GeoM) {
+	a, b, c, d := g.a1+1, g.b, g.c, g.d1+1
+	oa, ob, oc, od := other.a1+1, other.b, other.c, other.d1+1
+	g.a1 = oa*a + ob*c - 1
+	g.b = oa*b + ob*d
+	g.c = oc*a + od*c
+	g.d1 = oc*b + od*d - 1
+	g.tx, g.ty = oa*g.tx+ob*g.ty+other.tx, oc*g.tx+od*g.ty+other.ty
+}
+
+// Scale scales the matrix by (x, y).
+func (g *GeoM) Scale(x, y float64) {
+	g.Concat(GeoM{a1: x - 1, d1: y - 1})
+}
+
+// Translate translates the matrix by (tx, ty).
+func (g *GeoM) Translate(tx, ty float64) {
+	g.tx += tx
+	g.ty += ty
+}
+
+// Rotate rotates the matrix clockwise by theta in radians.
+func (g *GeoM) Rotate(theta float64) {
+	if theta == 0 {
+		return
+	}
+	sin, cos := math.Sincos(theta)
+	g.Concat(GeoM{a1: cos - 1, b: -sin, c: sin, d1: cos - 1})
+}
+
+func (g *GeoM) det() float64 {
+	return (g.a1+1)*(g.d1+1) - g.b*g.c
+}
+
+// IsInvertible returns true if the matrix is invertible.
+func (g *GeoM) IsInvertible() bool {
+	return g.det() != 0
+}
+
+// Invert inverts the matrix. It panics if the matrix is not invertible.
+func (g *GeoM) Invert() {
+	det := g.det()
+	if det == 0 {
+		panic("core: the matrix is not invertible")
+	}
+	a, b, c, d := g.a1+1, g.b, g.c, g.d1+1
+	*g = GeoM{
+		a1: d/det - 1,
+		b:  -b / det,
+		c:  -c / det,
+		d1: a/det - 1,
+		tx: (b*g.ty - d*g.tx) / det,
+		ty: (c*g.tx - a*g.ty) / det,
+	}
+}
@@ -0,0 +1,181 @@
+package core
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// GradientKind is the shape of a gradient.
+type GradientKind int
+
+const (
+	GradientLinear GradientKind = iota
+	GradientRadial
+)
+
+// ColorStop is a color at an offset along a gradient,
+// from 0 at the start of the gradient to 1 at its end.
+type ColorStop struct {
+	Offset float64
+	Color  color.Color
+}
+
+// Gradient is a background gradient that is stretched to the frame of the view.
+type Gradient struct {
+	Kind GradientKind
+	// Angle is the direction of a linear gradient in degrees as in CSS:
+	// 0 goes to the top, 90 to the right and 180 to the bottom.
+	Angle float64
+	// Circle makes a radial gradient a circle instead of an ellipse.
+	// A radial gradient is centered in the view and ends at its farthest corner.
+	Circle bool
+	Stops  []ColorStop
+}
+
+// cssBackground is the value of the background shorthand: a color or a gradient.
+type cssBackground struct {
+	color    color.Color
+	gradient *Gradient
+}
+
+// cssBackgroundImage is the value of background-image: an image name or a gradient.
+type cssBackgroundImage struct {
+	name     string
+	gradient *Gradient
+}
+
+func parseBackground(val string) (any, error) {
+	if isGradient(val) {
+		g, err := parseGradient(val)
+		if err != nil {
+			return nil, err
+		}
+		return cssBackground{gradient: g}, nil
+	}
+	c, err := parseColor(val)
+	if err != nil {
+		return nil, err
+	}
+	return cssBackground{color: c.(color.Color)}, nil
+}
+
+func parseBackgroundImage(val string) (any, error) {
+	if isGradient(val) {
+		g, err := parseGradient(val)
+		if err != nil {
+			return nil, err
+		}
+		return cssBackgroundImage{gradient: g}, nil
+	}
+	name, err := parseURL(val)
+	if err != nil {
+		return nil, err
+	}
+	return cssBackgroundImage{name: name.(string)}, nil
+}
+
+func isGradient(val string) bool {
+	return strings.HasPrefix(val, "linear-gradient(") || strings.HasPrefix(val, "radial-gradient(")
+}
+
+// parseGradient parses `linear-gradient(...)` or `radial-gradient(...)`.
+// Stop positions are percentages or fractions; stops without a position
+// are spread evenly between their neighbours.
+func parseGradient(val string) (*Gradient, error) {
+	open := strings.Index(val, "(")
+	if open < 0 || !strings.HasSuffix(val, ")") {
+		return nil, fmt.Errorf("invalid gradient: %s", val)
+	}
+	g := &Gradient{}
+	args := splitValues(val[open+1:len(val)-1], ',')
+	switch val[:open] {
+	case "linear-gradient":
+		g.Kind, g.Angle = GradientLinear, 180
+		if len(args) > 0 {
+			if a, ok := parseGradientDirection(args[0]); ok {
+				g.Angle = a
+				args = args[1:]
+			}
+		}
+	case "radial-gradient":
+		g.Kind = GradientRadial
+		if len(args) > 0 && (args[0] == "circle" || args[0] == "ellipse") {
+			g.Circle = args[0] == "circle"
+			args = args[1:]
+		}
+	}
+	if len(args) < 2 {
+		return nil, fmt.Errorf("gradient needs at least two color stops: %s", val)
+	}
+	known := make([]bool, len(args))
+	for i, arg := range args {
+		fs := splitValues(arg, ' ')
+		if len(fs) == 0 || len(fs) > 2 {
+			return nil, fmt.Errorf("invalid color stop: %s", arg)
+		}
+		c, err := parseColor(fs[0])
+		if err != nil {
+			return nil, err
+		}
+		s := ColorStop{Color: c.(color.Color)}
+		if len(fs) == 2 {
+			o, err := parseFraction(fs[1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid color stop: %s", arg)
+			}
+			s.Offset, known[i] = o.(float64), true
+		}
+		g.Stops = append(g.Stops, s)
+	}
+	spreadStops(g.Stops, known)
+	return g, nil
+}
+
+// spreadStops sets the offsets of the stops that are not known:
+// the first and last stops default to 0 and 1 and the others are spread evenly.
+func spreadStops(stops []ColorStop, known []bool) {
+	last := len(stops) - 1
+	if !known[0] {
+		stops[0].Offset, known[0] = 0, true
+	}
+	if !known[last] {
+		stops[last].Offset, known[last] = 1, true
+	}
+	prev := 0
+	for i := 1; i <= last; i++ {
+		if !known[i] {
+			continue
+		}
+		for j := prev + 1; j < i; j++ {
+			t := float64(j-prev) / float64(i-prev)
+			stops[j].Offset = stops[prev].Offset + (stops[i].Offset-stops[prev].Offset)*t
+		}
+		prev = i
+	}
+}
+
+// parseGradientDirection parses an angle such as `45deg` or a direction such as `to top right`.
+func parseGradientDirection(val string) (float64, bool) {
+	if strings.HasPrefix(val, "to ") {
+		angles := map[string]float64{
+			"top": 0, "top right": 45, "right top": 45,
+			"right": 90, "bottom right": 135, "right bottom": 135,
+			"bottom": 180, "bottom left": 225, "left bottom": 225,
+			"left": 270, "top left": 315, "left top": 315,
+		}
+		a, ok := angles[strings.Join(strings.Fields(val[len("to "):]), " ")]
+		return a, ok
+	}
+	for _, unit := range []string{"deg", "turn", "rad"} {
+		if strings.HasSuffix(val, unit) {
+			if _, err := strconv.ParseFloat(strings.TrimSuffix(val, unit), 64); err != nil {
+				return 0, false
+			}
+			a, err := parseAngle(val)
+			return a, err == nil
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,82 @@
+package core
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGradient(t *testing.T) {
+	red := color.RGBA{0xff, 0, 0, 0xff}
+	blue := color.RGBA{0, 0, 0xff, 0xff}
+	for _, tt := range []struct {
+		val  string
+		want *Gradient
+	}{
+		{
+			val: "linear-gradient(red, blue)",
+			want: &Gradient{Kind: GradientLinear, Angle: 180, Stops: []ColorStop{
+				{0, red}, {1, blue},
+			}},
+		},
+		{
+			val: "linear-gradient(to right, red, white, blue 80%)",
+			want: &Gradient{Kind: GradientLinear, Angle: 90, Stops: []ColorStop{
+				{0, red}, {.4, color.White}, {.8, blue},
+			}},
+		},
+		{
+			val: "linear-gradient(0.25turn, red 10%, blue)",
+			want: &Gradient{Kind: GradientLinear, Angle: 90, Stops: []ColorStop{
+				{.1, red}, {1, blue},
+			}},
+		},
+		{
+			val: "radial-gradient(circle, rgba(255, 0, 0, 1), blue)",
+			want: &Gradient{Kind: GradientRadial, Circle: true, Stops: []ColorStop{
+				{0, color.NRGBA{0xff, 0, 0, 0xff}}, {1, blue},
+			}},
+		},
+	} {
+		got, err := parseGradient(tt.val)
+		require.NoError(t, err, tt.val)
+		require.Equal(t, tt.want, got, tt.val)
+	}
+
+	for _, val := range []string{
+		"linear-gradient(red)",
+		"linear-gradient(to middle, red, blue)",
+		"linear-gradient(red, blue 1px)",
+		"radial-gradient(red, nocolor)",
+	} {
+		_, err := parseGradient(val)
+		require.Error(t, err, val)
+	}
+}
+
+func TestGradientBackground(t *testing.T) {
+	resetComponents()
+	v := Parse(`
+		<view style="width: 100; height: 100">
+			<view id="bar" style="width: 80; height: 10; border-radius: 5; background: linear-gradient(to right, red, green)"></view>
+			<view id="sheen" style="width: 20; height: 20; background-color: red; background-image: radial-gradient(white, transparent)"></view>
+			<view id="plain" style="background-image: url(panel)"></view>
+		</view>`, nil)
+
+	bar := v.MustGetByID("bar")
+	require.Nil(t, bar.BackgroundColor)
+	require.Equal(t, GradientLinear, bar.BackgroundGradient.Kind)
+	require.True(t, bar.shouldPaintBox())
+
+	sheen := v.MustGetByID("sheen")
+	require.NotNil(t, sheen.BackgroundColor)
+	require.Equal(t, GradientRadial, sheen.BackgroundGradient.Kind)
+
+	plain := v.MustGetByID("plain")
+	require.Equal(t, "panel", plain.BackgroundImage)
+	require.Nil(t, plain.BackgroundGradient)
+
+	v.Draw(image.NewRGBA(image.Rect(0, 0, 100, 100)))
+}
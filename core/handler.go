@@ -0,0 +1,245 @@
+package core
+
+import (
+	"image"
+)
+
+// Handler represents a component that can be added to a container.
+type Handler interface{}
+
+// Drawer represents a component that can be added to a container.
+type Drawer interface {
+	// Draw function draws the content of the component inside the frame.
+	Draw(screen Image, frame image.Rectangle, v *View)
+}
+
+// Updater represents a component that updates by one tick.
+type Updater interface {
+	// Update updates the state of the component by one tick.
+	Update(v *View)
+}
+
+// DrawHandler represents a component that can be added to a container.
+// Deprectead: use Drawer instead
+type DrawHandler interface {
+	// HandleDraw function draws the content of the component inside the frame.
+	// The frame parameter represents the location (x,y) and size (width,height) relative to the window (0,0).
+	HandleDraw(screen Image, frame image.Rectangle)
+}
+
+// UpdateHandler represents a component that updates by one tick.
+// Deprectead: use Updater instead
+type UpdateHandler interface {
+	// Updater updates the state of the component by one tick.
+	HandleUpdate()
+}
+
+// ButtonHandler represents a button component.
+type ButtonHandler interface {
+	// HandlePress handle the event when user just started pressing the button
+	// The parameter (x, y) is the location relative to the window (0,0).
+	// touchID is the unique ID of the touch.
+	// If the button is pressed by a mouse, touchID is -1.
+	HandlePress(x, y int, t TouchID)
+
+	// HandleRelease handle the event when user just released the button.
+	// The parameter (x, y) is the location relative to the window (0,0).
+	// The parameter isCancel is true when the touch/left click is released outside of the button.
+	HandleRelease(x, y int, isCancel bool)
+}
+
+// NotButton represents a component that is not a button.
+// TODO: update HandlePress to return bool in the next major version.
+type NotButton interface {
+	// IsButton returns true if the handler is a button.
+	IsButton() bool
+}
+
+// BoxPaintHandler represents a component that lets the view paint its box
+// (background and border) from the style before the component draws.
+// Views without a handler always paint their box.
+type BoxPaintHandler interface {
+	// PaintsBox returns true if the view should paint its box.
+	PaintsBox() bool
+}
+
+// TextPaintHandler represents a component that lets the view draw its text
+// from the style before the component draws.
+// Views without a handler always draw their text.
+type TextPaintHandler interface {
+	// PaintsText returns true if the view should draw its text.
+	PaintsText() bool
+}
+
+// TouchHandler represents a component that handle touches.
+type TouchHandler interface {
+	// HandleJustPressedTouchID handles the touchID just pressed and returns true if it handles the TouchID
+	HandleJustPressedTouchID(touch TouchID, x, y int) bool
+	// HandleJustReleasedTouchID handles the touchID just released
+	// Should be called only when it handled the TouchID when pressed
+	HandleJustReleasedTouchID(touch TouchID, x, y int)
+}
+
+// MouseHandler represents a component that handle mouse move.
+type MouseHandler interface {
+	// HandleMouse handles the mouch move and returns true if it handle the mouse move.
+	// The parameter (x, y) is the location relative to the window (0,0).
+	HandleMouse(x, y int) bool
+}
+
+// MouseLeftButtonHandler represents a component that handle mouse button left click.
+type MouseLeftButtonHandler interface {
+	// HandleJustPressedMouseButtonLeft handle left mouse button click just pressed.
+	// The parameter (x, y) is the location relative to the window (0,0).
+	// It returns true if it handles the mouse move.
+	HandleJustPressedMouseButtonLeft(x, y int) bool
+	// HandleJustReleasedTouchID handles the touchID just released.
+	// The parameter (x, y) is the location relative to the window (0,0).
+	HandleJustReleasedMouseButtonLeft(x, y int)
+}
+
+// WheelHandler represents a component that handles the mouse wheel.
+type WheelHandler interface {
+	// HandleWheel handles the scroll (dx, dy) of the mouse wheel over the view
+	// and returns true if it handles it. Unhandled scrolls are passed to the ancestors.
+	// The parameter (x, y) is the location of the cursor relative to the window (0,0).
+	HandleWheel(x, y int, dx, dy float64) bool
+}
+
+// MouseButtonHandler represents a component that handles the buttons of the mouse.
+type MouseButtonHandler interface {
+	// HandleJustPressedMouseButton handles the mouse button just pressed inside the view.
+	// The parameter (x, y) is the location relative to the window (0,0).
+	// It returns true if it handles the button.
+	HandleJustPressedMouseButton(b MouseButton, x, y int) bool
+	// HandleJustReleasedMouseButton handles the mouse button just released.
+	// It is called only when it handled the button when pressed.
+	// The parameter isCancel is true when the button is released outside of the view.
+	HandleJustReleasedMouseButton(b MouseButton, x, y int, isCancel bool)
+}
+
+// MouseEnterHandler represets a component that handle mouse enter.
+type MouseEnterLeaveHandler interface {
+	// HandleMouseEnter handles the mouse enter.
+	HandleMouseEnter(x, y int) bool
+	// HandleMouseLeave handles the mouse leave.
+	HandleMouseLeave()
+}
+
+// SwipeHandler represents different swipe directions.
+type SwipeDirection int
+
+const (
+	SwipeDirectionLeft SwipeDirection = iota
+	SwipeDirectionRight
+	SwipeDirectionUp
+	SwipeDirectionDown
+)
+
+// SwipeHandler represents a component that handle swipe.
+type SwipeHandler interface {
+	// HandleSwipe handles swipes.
+	HandleSwipe(dir SwipeDirection)
+}
+
+type handler struct {
+	opts HandlerOpts
+}
+
+// HandlerOpts represents the options for a handler.
+type HandlerOpts struct {
+	Update func(v *View)
+	Draw   func(screen Image, frame image.Rectangle, v *View)
+	// DrawWithContext is used instead of Draw if it is set.
+	DrawWithContext func(ctx *DrawContext)
+	HandlePress     func(x, y int, t TouchID)
+	HandleRelease   func(x, y int, isCancel bool)
+}
+
+// NewHandler creates a new handler.
+func NewHandler(opts HandlerOpts) Handler {
+	return &handler{opts: opts}
+}
+
+func (h *handler) Update(v *View) {
+	if h.opts.Update != nil {
+		h.opts.Update(v)
+	}
+}
+
+func (h *handler) Draw(screen Image, frame image.Rectangle, v *View) {
+	if h.opts.Draw != nil {
+		h.opts.Draw(screen, frame, v)
+	}
+}
+
+func (h *handler) DrawWithContext(ctx *DrawContext) {
+	if h.opts.DrawWithContext != nil {
+		h.opts.DrawWithContext(ctx)
+		return
+	}
+	h.Draw(ctx.Screen, ctx.Frame, ctx.View)
+}
+
+func (h *handler) HandlePress(x, y int, t TouchID) {
+	if h.opts.HandlePress != nil {
+		h.opts.HandlePress(x, y, t)
+	}
+}
+
+func (h *handler) HandleRelease(x, y int, isCancel bool) {
+	if h.opts.HandleRelease != nil {
+		h.opts.HandleRelease(x, y, isCancel)
+	}
+}
+
+// HandlerAdapter lets the handlers that are written with the types of an engine,
+// such as the screen image and the touch IDs, handle the drawing and the events of views.
+// The engine adapter sets it with SetHandlerAdapter.
+type HandlerAdapter interface {
+	// Draw draws h and returns true if it is a drawer of the engine.
+	Draw(h Handler, ctx *DrawContext) bool
+	// ButtonHandler returns h as a ButtonHandler if it is a button of the engine.
+	ButtonHandler(h Handler) (ButtonHandler, bool)
+	// TouchHandler returns h as a TouchHandler if it is a touch handler of the engine.
+	TouchHandler(h Handler) (TouchHandler, bool)
+	// MouseButtonHandler returns h as a MouseButtonHandler if it is a mouse button handler of the engine.
+	MouseButtonHandler(h Handler) (MouseButtonHandler, bool)
+}
+
+var handlerAdapter HandlerAdapter
+
+// SetHandlerAdapter sets the adapter of the handlers of an engine.
+func SetHandlerAdapter(a HandlerAdapter) {
+	handlerAdapter = a
+}
+
+func asButtonHandler(h Handler) (ButtonHandler, bool) {
+	if b, ok := h.(ButtonHandler); ok {
+		return b, true
+	}
+	if handlerAdapter != nil && h != nil {
+		return handlerAdapter.ButtonHandler(h)
+	}
+	return nil, false
+}
+
+func asTouchHandler(h Handler) (TouchHandler, bool) {
+	if t, ok := h.(TouchHandler); ok {
+		return t, true
+	}
+	if handlerAdapter != nil && h != nil {
+		return handlerAdapter.TouchHandler(h)
+	}
+	return nil, false
+}
+
+func asMouseButtonHandler(h Handler) (MouseButtonHandler, bool) {
+	if m, ok := h.(MouseButtonHandler); ok {
+		return m, true
+	}
+	if handlerAdapter != nil && h != nil {
+		return handlerAdapter.MouseButtonHandler(h)
+	}
+	return nil, false
+}
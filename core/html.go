@@ -0,0 +1,1163 @@
+package core
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/vanng822/go-premailer/premailer"
+	"golang.org/x/net/html"
+)
+
+// The Component can be either a handler instance (e.g., DrawHandler), a factory function
+// func() furex.Handler, or a function component func() *furex.View.
+// This allows flexibility in usage:
+// If you want to reuse the same handler instance for multiple HTML tags, pass the instance;
+// otherwise, pass the factory function to create separate handler instances for each tag.
+type Component interface{}
+
+// ComponentsMap is a type alias for a dictionary that associates
+// custom HTML tags with their respective components.
+// This enables a convenient way to manage and reference components
+// based on their corresponding HTML tags.
+type ComponentsMap map[string]Component
+
+// ParseOptions represents options for parsing HTML.
+type ParseOptions struct {
+	// Components is a map of component name and handler.
+	// For example, if you have a component named "start-button", you can define a handler
+	// for it like this:
+	// 	opts.Components := map[string]Handler{
+	// 		"start-button": <your handler>,
+	//  }
+	// The component name must be in kebab-case.
+	// You can use the component in your HTML like this:
+	// 	<start-button></start-button>
+	// Note: self closing tag is not supported.
+	Components ComponentsMap
+	// Width and Height is the size of the root view.
+	// This is useful when you want to specify the width and height
+	// outside of the HTML.
+	Width  int
+	Height int
+
+	// Handler is the handler for the root view.
+	Handler Handler
+}
+
+func Parse(input string, opts *ParseOptions) *View {
+	if opts == nil {
+		opts = &ParseOptions{}
+	}
+
+	doc, keyframes := extractKeyframes(input)
+	doc, sheet := extractMediaBlocks(doc)
+	inlinedHTML := inlineCSS(doc)
+	z := html.NewTokenizer(strings.NewReader(inlinedHTML))
+	dummy := &View{}
+	views := []*View{}
+	stack := &stack{stack: []*View{dummy}}
+	depth := 0
+	inBody := false
+	cms := []ComponentsMap{opts.Components, registerdComponents}
+	texts := map[*View]*richText{}
+Loop:
+	for {
+		tt := z.Next()
+		tn, _ := z.TagName()
+		switch tt {
+		case html.ErrorToken:
+			if z.Err() == io.EOF {
+				break Loop
+			}
+			panic(z.Err())
+		case html.StartTagToken:
+			if string(tn) == "body" {
+				inBody = true
+				continue
+			}
+			if !inBody {
+				continue
+			}
+			if stack.len() > 1 && isInlineTag(string(tn), cms) {
+				richTextOf(texts, stack.peek()).open(string(tn), readAttrs(z), false)
+				continue
+			}
+			view := processTag(z, string(tn), opts, depth, cms)
+			if view == nil {
+				continue
+			}
+			views = append(views, view)
+			delete(texts, stack.peek())
+			stack.peek().AddChild(view)
+			stack.push(view)
+
+			depth++
+		case html.SelfClosingTagToken:
+			if stack.len() > 1 && isInlineTag(string(tn), cms) {
+				richTextOf(texts, stack.peek()).open(string(tn), readAttrs(z), true)
+				continue
+			}
+			view := processTag(z, string(tn), opts, depth, cms)
+			if view == nil {
+				continue
+			}
+			views = append(views, view)
+			delete(texts, stack.peek())
+			stack.peek().AddChild(view)
+		case html.TextToken:
+			if stack.len() > 0 {
+				t := string(z.Text())
+				stack.peek().Text = strings.TrimSpace(t)
+				richTextOf(texts, stack.peek()).text(t)
+			}
+		case html.EndTagToken:
+			if string(tn) == "body" {
+				inBody = false
+				continue
+			}
+			if !inBody {
+				continue
+			}
+			if stack.len() > 1 && isInlineTag(string(tn), cms) {
+				richTextOf(texts, stack.peek()).close(string(tn))
+				continue
+			}
+			view := stack.pop()
+			if r := texts[view]; r != nil && r.inline {
+				view.Spans = normalizeSpans(r.spans)
+				view.Text = spansText(view.Spans)
+			}
+			depth--
+		}
+	}
+	if len(dummy.children) != 1 {
+		panic(fmt.Sprintf("invalid html: %s", input))
+	}
+	view := dummy.PopChild()
+	// the root view should be dirty for the first time
+	// even if the view does not have any children
+	view.isDirty = true
+	if opts.Handler != nil {
+		view.Handler = opts.Handler
+	}
+	if len(keyframes) > 0 {
+		view.keyframes = keyframes
+	}
+	if len(sheet.blocks) > 0 {
+		setMediaSheet(sheet, views, cms)
+		view.applyMediaQueries(view.Width, view.Height)
+	}
+	return view
+}
+
+// collectStyles returns the style attribute of each element of an inlined document
+// in the same order that Parse creates the views. Inline elements are skipped.
+func collectStyles(inlinedHTML string, cms cms) []string {
+	z := html.NewTokenizer(strings.NewReader(inlinedHTML))
+	styles := []string{}
+	inBody := false
+	for {
+		tt := z.Next()
+		tn, _ := z.TagName()
+		switch tt {
+		case html.ErrorToken:
+			return styles
+		case html.StartTagToken:
+			if string(tn) == "body" {
+				inBody = true
+				continue
+			}
+			if !inBody || isInlineTag(string(tn), cms) {
+				continue
+			}
+			styles = append(styles, readAttrs(z).style)
+		case html.SelfClosingTagToken:
+			if isInlineTag(string(tn), cms) {
+				continue
+			}
+			styles = append(styles, readAttrs(z).style)
+		case html.EndTagToken:
+			if string(tn) == "body" {
+				inBody = false
+			}
+		}
+	}
+}
+
+func inlineCSS(doc string) string {
+	prem, err := premailer.NewPremailerFromString(doc, &premailer.Options{})
+	if err != nil {
+		warnf("invalid css: %s", err)
+		return doc
+	}
+	html, err := prem.Transform()
+	if err != nil {
+		warnf("error transform html: %s", err)
+		return doc
+	}
+	return html
+}
+
+type stack struct {
+	stack []*View
+}
+
+func (s *stack) push(v *View) {
+	s.stack = append(s.stack, v)
+}
+
+func (s *stack) len() int {
+	return len(s.stack)
+}
+
+func (s *stack) peek() *View {
+	return s.stack[len(s.stack)-1]
+}
+
+func (s *stack) pop() *View {
+	v := s.peek()
+	s.stack = s.stack[:len(s.stack)-1]
+	return v
+}
+
+var (
+	defaultComponents   = ComponentsMap{"div": nil, "view": nil}
+	registerdComponents = defaultComponents
+)
+
+func RegisterComponents(cs ComponentsMap) {
+	for k, v := range cs {
+		register(k, v)
+	}
+}
+
+func register(name string, c Component) { registerdComponents[name] = c }
+func resetComponents()                  { registerdComponents = defaultComponents }
+
+type cms []ComponentsMap
+
+func processTag(z *html.Tokenizer, tagName string, opts *ParseOptions, depth int, cms cms) *View {
+	view := createView(tagName, cms)
+
+	if depth == 0 {
+		processRootView(view, opts)
+	}
+
+	view.TagName = tagName
+	view.Raw = string(z.Raw())
+
+	setStyleProps(view, readAttrs(z))
+
+	return view
+}
+
+func setStyleProps(view *View, attrs attrs) {
+	view.css = &cssState{initial: view.saveStyle(), base: attrs.style, applied: attrs.style}
+	parseStyle(view, attrs.style)
+
+	view.ID = attrs.id
+	view.Attrs = attrs.miscs
+	view.Hidden = attrs.hidden
+}
+
+func processRootView(view *View, opts *ParseOptions) {
+	if opts.Width != 0 {
+		view.Width = opts.Width
+	}
+	if opts.Height != 0 {
+		view.Height = opts.Height
+	}
+}
+
+func createView(name string, cms cms) *View {
+	view := &View{}
+	for _, cm := range cms {
+		if ok := component(name, cm, view); ok {
+			return view
+		}
+	}
+	panic(fmt.Sprintf("unknown component: %s", name))
+}
+
+func component(name string, m ComponentsMap, v *View) bool {
+	c, ok := m[name]
+	if c == nil {
+		return ok
+	}
+	if c, ok := c.(func() Handler); ok {
+		v.Handler = c()
+		return true
+	}
+	if c, ok := c.(func() *View); ok {
+		*v = *c()
+		return true
+	}
+	v.Handler = c
+	return true
+}
+
+func parseStyle(view *View, style string) {
+	if errs := applyStyle(view, style); errs.HasErrors() {
+		warnf("parse style errors: %v", errs)
+	}
+}
+
+// applyStyle sets the properties of the CSS declarations of style to the view.
+// It returns the declarations that can't be applied.
+func applyStyle(view *View, style string) *ErrorList {
+	pairs := strings.Split(style, ";")
+	errs := &ErrorList{}
+	for _, pair := range pairs {
+		kv := strings.SplitN(pair, ":", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		k := strings.TrimSpace(kv[0])
+		v := strings.TrimSpace(kv[1])
+
+		mapper, ok := styleMapper[k]
+		if !ok {
+			errs.Add(fmt.Errorf("unknown style: %s", k))
+			continue
+		}
+		parsed, err := mapper.parseFunc(v)
+		if err != nil {
+			errs.Add(err)
+			continue
+		}
+		mapper.setFunc(view, parsed)
+	}
+	return errs
+}
+
+func Int(i int) *int { return &i }
+
+func Float(f float64) *float64 { return &f }
+
+// cssState keeps the styles a parsed view was created with
+// so that they can be re-applied when the media queries change.
+type cssState struct {
+	initial viewStyle
+	// base is the style from the style attribute and the rules outside of @media blocks.
+	base string
+	// applied is the style from the stylesheets that the view has, with the matching @media rules.
+	applied string
+	// sheet computes the styles with @media rules of the document. index is the view in it.
+	sheet *mediaSheet
+	index int
+	// style is the declarations set with SetStyle.
+	style string
+}
+
+// viewStyle is the set of view properties that can be set by CSS.
+type viewStyle struct {
+	Left         int
+	Right        *int
+	Top          int
+	Bottom       *int
+	Width        int
+	WidthInPct   float64
+	Height       int
+	HeightInPct  float64
+	MarginLeft   int
+	MarginTop    int
+	MarginRight  int
+	MarginBottom int
+	Position     Position
+	Direction    Direction
+	Wrap         FlexWrap
+	Justify      Justify
+	AlignItems   AlignItem
+	AlignContent AlignContent
+	Grow         float64
+	Shrink       float64
+	Display      Display
+	AnchorLeft   float64
+	AnchorTop    float64
+	AnchorRight  float64
+	AnchorBottom float64
+	OffsetLeft   int
+	OffsetTop    int
+	OffsetRight  int
+	OffsetBottom int
+	PivotX       float64
+	PivotY       float64
+
+	BackgroundColor    color.Color
+	BorderWidth        int
+	BorderColor        color.Color
+	BorderRadius       int
+	BackgroundImage    string
+	BackgroundSlice    Insets
+	BackgroundSize     BackgroundSize
+	BoxShadows         []BoxShadow
+	BackgroundGradient *Gradient
+	Opacity            *float64
+	ColorM             *ColorM
+	Transform          *Transform
+	Transitions        []Transition
+	KeyframeAnimations []KeyframeAnimation
+	Cache              bool
+	FontFamily         string
+	FontSize           int
+	Color              color.Color
+	TextAlign          TextAlign
+	LineHeight         float64
+	WhiteSpace         WhiteSpace
+	TextOverflow       TextOverflow
+}
+
+func (v *View) saveStyle() viewStyle {
+	return viewStyle{
+		Left:         v.Left,
+		Right:        v.Right,
+		Top:          v.Top,
+		Bottom:       v.Bottom,
+		Width:        v.Width,
+		WidthInPct:   v.WidthInPct,
+		Height:       v.Height,
+		HeightInPct:  v.HeightInPct,
+		MarginLeft:   v.MarginLeft,
+		MarginTop:    v.MarginTop,
+		MarginRight:  v.MarginRight,
+		MarginBottom: v.MarginBottom,
+		Position:     v.Position,
+		Direction:    v.Direction,
+		Wrap:         v.Wrap,
+		Justify:      v.Justify,
+		AlignItems:   v.AlignItems,
+		AlignContent: v.AlignContent,
+		Grow:         v.Grow,
+		Shrink:       v.Shrink,
+		Display:      v.Display,
+		AnchorLeft:   v.AnchorLeft,
+		AnchorTop:    v.AnchorTop,
+		AnchorRight:  v.AnchorRight,
+		AnchorBottom: v.AnchorBottom,
+		OffsetLeft:   v.OffsetLeft,
+		OffsetTop:    v.OffsetTop,
+		OffsetRight:  v.OffsetRight,
+		OffsetBottom: v.OffsetBottom,
+		PivotX:       v.PivotX,
+		PivotY:       v.PivotY,
+
+		BackgroundColor:    v.BackgroundColor,
+		BorderWidth:        v.BorderWidth,
+		BorderColor:        v.BorderColor,
+		BorderRadius:       v.BorderRadius,
+		BackgroundImage:    v.BackgroundImage,
+		BackgroundSlice:    v.BackgroundSlice,
+		BackgroundSize:     v.BackgroundSize,
+		BoxShadows:         v.BoxShadows,
+		BackgroundGradient: v.BackgroundGradient,
+		Opacity:            v.Opacity,
+		ColorM:             v.ColorM,
+		Transform:          v.Transform,
+		Transitions:        v.Transitions,
+		KeyframeAnimations: v.KeyframeAnimations,
+		Cache:              v.Cache,
+		FontFamily:         v.FontFamily,
+		FontSize:           v.FontSize,
+		Color:              v.Color,
+		TextAlign:          v.TextAlign,
+		LineHeight:         v.LineHeight,
+		WhiteSpace:         v.WhiteSpace,
+		TextOverflow:       v.TextOverflow,
+	}
+}
+
+func (v *View) restoreStyle(s viewStyle) {
+	v.Left = s.Left
+	v.Right = s.Right
+	v.Top = s.Top
+	v.Bottom = s.Bottom
+	v.Width = s.Width
+	v.WidthInPct = s.WidthInPct
+	v.Height = s.Height
+	v.HeightInPct = s.HeightInPct
+	v.MarginLeft = s.MarginLeft
+	v.MarginTop = s.MarginTop
+	v.MarginRight = s.MarginRight
+	v.MarginBottom = s.MarginBottom
+	v.Position = s.Position
+	v.Direction = s.Direction
+	v.Wrap = s.Wrap
+	v.Justify = s.Justify
+	v.AlignItems = s.AlignItems
+	v.AlignContent = s.AlignContent
+	v.Grow = s.Grow
+	v.Shrink = s.Shrink
+	v.Display = s.Display
+	v.AnchorLeft = s.AnchorLeft
+	v.AnchorTop = s.AnchorTop
+	v.AnchorRight = s.AnchorRight
+	v.AnchorBottom = s.AnchorBottom
+	v.OffsetLeft = s.OffsetLeft
+	v.OffsetTop = s.OffsetTop
+	v.OffsetRight = s.OffsetRight
+	v.OffsetBottom = s.OffsetBottom
+	v.PivotX = s.PivotX
+	v.PivotY = s.PivotY
+	v.BackgroundColor = s.BackgroundColor
+	v.BorderWidth = s.BorderWidth
+	v.BorderColor = s.BorderColor
+	v.BorderRadius = s.BorderRadius
+	v.BackgroundImage = s.BackgroundImage
+	v.BackgroundSlice = s.BackgroundSlice
+	v.BackgroundSize = s.BackgroundSize
+	v.BoxShadows = s.BoxShadows
+	v.BackgroundGradient = s.BackgroundGradient
+	v.Opacity = s.Opacity
+	v.ColorM = s.ColorM
+	v.Transform = s.Transform
+	v.Transitions = s.Transitions
+	v.KeyframeAnimations = s.KeyframeAnimations
+	v.Cache = s.Cache
+	v.FontFamily = s.FontFamily
+	v.FontSize = s.FontSize
+	v.Color = s.Color
+	v.TextAlign = s.TextAlign
+	v.LineHeight = s.LineHeight
+	v.WhiteSpace = s.WhiteSpace
+	v.TextOverflow = s.TextOverflow
+}
+
+var styleMapper = map[string]mapper[View]{
+	"left": {
+		parseFunc: parseNumber,
+		setFunc:   setFunc(func(v *View, val int) { v.Left = val }),
+	},
+	"right": {
+		parseFunc: parseNumber,
+		setFunc:   setFunc(func(v *View, val int) { v.Right = Int(val) }),
+	},
+	"top": {
+		parseFunc: parseNumber,
+		setFunc:   setFunc(func(v *View, val int) { v.Top = val }),
+	},
+	"bottom": {
+		parseFunc: parseNumber,
+		setFunc:   setFunc(func(v *View, val int) { v.Bottom = Int(val) }),
+	},
+	"width": {
+		parseFunc: parseLength,
+		setFunc: setFunc(func(v *View, val cssLength) {
+			switch val.unit {
+			case cssUnitPx:
+				v.Width = int(val.val)
+			case cssUnitPct:
+				v.WidthInPct = val.val
+			}
+		}),
+	},
+	"height": {
+		parseFunc: parseLength,
+		setFunc: setFunc(func(v *View, val cssLength) {
+			switch val.unit {
+			case cssUnitPx:
+				v.Height = int(val.val)
+			case cssUnitPct:
+				v.HeightInPct = val.val
+			}
+		}),
+	},
+	"margin-left": {
+		parseFunc: parseNumber,
+		setFunc:   setFunc(func(v *View, val int) { v.MarginLeft = val }),
+	},
+	"margin-top": {
+		parseFunc: parseNumber,
+		setFunc:   setFunc(func(v *View, val int) { v.MarginTop = val }),
+	},
+	"margin-right": {
+		parseFunc: parseNumber,
+		setFunc:   setFunc(func(v *View, val int) { v.MarginRight = val }),
+	},
+	"margin-bottom": {
+		parseFunc: parseNumber,
+		setFunc:   setFunc(func(v *View, val int) { v.MarginBottom = val }),
+	},
+	"position": {
+		parseFunc: parsePosition,
+		setFunc:   setFunc(func(v *View, val Position) { v.Position = val }),
+	},
+	"direction": {
+		parseFunc: parseDirection,
+		setFunc:   setFunc(func(v *View, val Direction) { v.Direction = val }),
+	},
+	"flex-direction": {
+		parseFunc: parseDirection,
+		setFunc:   setFunc(func(v *View, val Direction) { v.Direction = val }),
+	},
+	"flex-wrap": {
+		parseFunc: parseWrap,
+		setFunc:   setFunc(func(v *View, val FlexWrap) { v.Wrap = val }),
+	},
+	"wrap": {
+		parseFunc: parseWrap,
+		setFunc:   setFunc(func(v *View, val FlexWrap) { v.Wrap = val }),
+	},
+	"justify": {
+		parseFunc: parseJustify,
+		setFunc:   setFunc(func(v *View, val Justify) { v.Justify = val }),
+	},
+	"justify-content": {
+		parseFunc: parseJustify,
+		setFunc:   setFunc(func(v *View, val Justify) { v.Justify = val }),
+	},
+	"align-items": {
+		parseFunc: parseAlignItem,
+		setFunc:   setFunc(func(v *View, val AlignItem) { v.AlignItems = val }),
+	},
+	"align-content": {
+		parseFunc: parseAlignContent,
+		setFunc:   setFunc(func(v *View, val AlignContent) { v.AlignContent = val }),
+	},
+	"flex-grow": {
+		parseFunc: parseFloat,
+		setFunc:   setFunc(func(v *View, val float64) { v.Grow = val }),
+	},
+	"grow": {
+		parseFunc: parseFloat,
+		setFunc:   setFunc(func(v *View, val float64) { v.Grow = val }),
+	},
+	"flex-shrink": {
+		parseFunc: parseFloat,
+		setFunc:   setFunc(func(v *View, val float64) { v.Shrink = val }),
+	},
+	"shrink": {
+		parseFunc: parseFloat,
+		setFunc:   setFunc(func(v *View, val float64) { v.Shrink = val }),
+	},
+	"display": {
+		parseFunc: parseDisplay,
+		setFunc:   setFunc(func(v *View, val Display) { v.Display = val }),
+	},
+	"anchor": {
+		parseFunc: parseAnchorPreset,
+		setFunc:   setFunc(func(v *View, val AnchorPreset) { v.setAnchorPreset(val) }),
+	},
+	"anchor-left": {
+		parseFunc: parseFraction,
+		setFunc:   setFunc(func(v *View, val float64) { v.AnchorLeft = val }),
+	},
+	"anchor-top": {
+		parseFunc: parseFraction,
+		setFunc:   setFunc(func(v *View, val float64) { v.AnchorTop = val }),
+	},
+	"anchor-right": {
+		parseFunc: parseFraction,
+		setFunc:   setFunc(func(v *View, val float64) { v.AnchorRight = val }),
+	},
+	"anchor-bottom": {
+		parseFunc: parseFraction,
+		setFunc:   setFunc(func(v *View, val float64) { v.AnchorBottom = val }),
+	},
+	"offset-left": {
+		parseFunc: parseNumber,
+		setFunc:   setFunc(func(v *View, val int) { v.OffsetLeft = val }),
+	},
+	"offset-top": {
+		parseFunc: parseNumber,
+		setFunc:   setFunc(func(v *View, val int) { v.OffsetTop = val }),
+	},
+	"offset-right": {
+		parseFunc: parseNumber,
+		setFunc:   setFunc(func(v *View, val int) { v.OffsetRight = val }),
+	},
+	"offset-bottom": {
+		parseFunc: parseNumber,
+		setFunc:   setFunc(func(v *View, val int) { v.OffsetBottom = val }),
+	},
+	"pivot-x": {
+		parseFunc: parseFraction,
+		setFunc:   setFunc(func(v *View, val float64) { v.PivotX = val }),
+	},
+	"pivot-y": {
+		parseFunc: parseFraction,
+		setFunc:   setFunc(func(v *View, val float64) { v.PivotY = val }),
+	},
+	"background": {
+		parseFunc: parseBackground,
+		setFunc: setFunc(func(v *View, val cssBackground) {
+			v.BackgroundColor, v.BackgroundGradient = val.color, val.gradient
+		}),
+	},
+	"background-color": {
+		parseFunc: parseColor,
+		setFunc:   setFunc(func(v *View, val color.Color) { v.BackgroundColor = val }),
+	},
+	"background-image": {
+		parseFunc: parseBackgroundImage,
+		setFunc: setFunc(func(v *View, val cssBackgroundImage) {
+			v.BackgroundImage, v.BackgroundGradient = val.name, val.gradient
+		}),
+	},
+	"background-slice": {
+		parseFunc: parseInsets,
+		setFunc:   setFunc(func(v *View, val Insets) { v.BackgroundSlice = val }),
+	},
+	"background-size": {
+		parseFunc: parseBackgroundSize,
+		setFunc:   setFunc(func(v *View, val BackgroundSize) { v.BackgroundSize = val }),
+	},
+	"border": {
+		parseFunc: parseBorder,
+		setFunc: setFunc(func(v *View, val cssBorder) {
+			v.BorderWidth = val.width
+			v.BorderColor = val.color
+		}),
+	},
+	"border-width": {
+		parseFunc: parseNumber,
+		setFunc:   setFunc(func(v *View, val int) { v.BorderWidth = val }),
+	},
+	"border-color": {
+		parseFunc: parseColor,
+		setFunc:   setFunc(func(v *View, val color.Color) { v.BorderColor = val }),
+	},
+	"border-radius": {
+		parseFunc: parseNumber,
+		setFunc:   setFunc(func(v *View, val int) { v.BorderRadius = val }),
+	},
+	"box-shadow": {
+		parseFunc: parseBoxShadow,
+		setFunc:   setFunc(func(v *View, val []BoxShadow) { v.BoxShadows = val }),
+	},
+	"filter": {
+		parseFunc: parseFilter,
+		setFunc:   setFunc(func(v *View, val *ColorM) { v.ColorM = val }),
+	},
+	"opacity": {
+		parseFunc: parseOpacity,
+		setFunc:   setFunc(func(v *View, val float64) { v.Opacity = Float(val) }),
+	},
+	"transform": {
+		parseFunc: parseTransform,
+		setFunc:   setFunc(func(v *View, val transformValue) { v.setTransform(val) }),
+	},
+	"transform-origin": {
+		parseFunc: parseTransformOrigin,
+		setFunc:   setFunc(func(v *View, val [2]float64) { v.setTransformOrigin(val) }),
+	},
+	"transition": {
+		parseFunc: parseTransition,
+		setFunc:   setFunc(func(v *View, val []Transition) { v.Transitions = val }),
+	},
+	"animation": {
+		parseFunc: parseAnimation,
+		setFunc:   setFunc(func(v *View, val []KeyframeAnimation) { v.KeyframeAnimations = val }),
+	},
+	"font-family": {
+		parseFunc: func(val string) (any, error) { return val, nil },
+		setFunc:   setFunc(func(v *View, val string) { v.FontFamily = val }),
+	},
+	"font-size": {
+		parseFunc: parseNumber,
+		setFunc:   setFunc(func(v *View, val int) { v.FontSize = val }),
+	},
+	"color": {
+		parseFunc: parseColor,
+		setFunc:   setFunc(func(v *View, val color.Color) { v.Color = val }),
+	},
+	"text-align": {
+		parseFunc: parseTextAlign,
+		setFunc:   setFunc(func(v *View, val TextAlign) { v.TextAlign = val }),
+	},
+	"line-height": {
+		parseFunc: parseLineHeight,
+		setFunc:   setFunc(func(v *View, val float64) { v.LineHeight = val }),
+	},
+	"white-space": {
+		parseFunc: parseWhiteSpace,
+		setFunc:   setFunc(func(v *View, val WhiteSpace) { v.WhiteSpace = val }),
+	},
+	"text-overflow": {
+		parseFunc: parseTextOverflow,
+		setFunc:   setFunc(func(v *View, val TextOverflow) { v.TextOverflow = val }),
+	},
+	"will-change": {
+		parseFunc: parseWillChange,
+		setFunc:   setFunc(func(v *View, val bool) { v.Cache = val }),
+	},
+}
+
+// setFunc creates a function that takes an entity and a value as an interface{}.
+// The created function type asserts the value to the correct type U and then calls
+// the given function f with the entity and the value of type U.
+// If the type U is a pointer, the function will handle it accordingly:
+// - If the input value is nil, it will pass a nil value of type U to the given function f.
+// - If the input value is non-nil, it will create a pointer to the value, type-assert it to U, and pass it to f.
+func setFunc[T, U any](f func(entity T, value U)) func(T, any) {
+	return func(e T, v any) {
+		argType := reflect.TypeOf(f).In(1)
+
+		// If the value v is nil
+		if v == nil {
+			if argType.Kind() == reflect.Ptr {
+				// pass nil value if the type is pointer
+				nilValue := reflect.Zero(argType).Interface().(U)
+				f(e, nilValue)
+			} else {
+				// pass deafult value if the type is not pointer
+				var u U
+				defaultValue := reflect.Zero(reflect.TypeOf(u))
+				f(e, defaultValue.Interface().(U))
+			}
+			return
+		}
+
+		// If the second input parameter type of f is a pointer
+		if argType.Kind() == reflect.Ptr {
+			// Create a pointer to the value and set its value to v
+			valuePtr := reflect.New(reflect.TypeOf(v)).Elem()
+			valuePtr.Set(reflect.ValueOf(v))
+
+			// If the pointer created can be type asserted to U, call f with e and the pointer
+			if ptr, ok := valuePtr.Addr().Interface().(U); ok {
+				f(e, ptr)
+				return
+			}
+		}
+
+		// If the value v is of type U, call f directly with e and v
+		if v, ok := v.(U); ok {
+			f(e, v)
+			return
+		}
+
+		// If the type of the value is incorrect, panic with an error message
+		var u U
+		panic(fmt.Sprintf("type of the value is incorrect: %v, %T vs %T", v, v, u))
+	}
+}
+
+type mapper[T any] struct {
+	parseFunc func(string) (any, error)
+	setFunc   func(*T, any)
+}
+
+func parseNumber(val string) (any, error) {
+	val = strings.TrimSuffix(val, "px")
+	return strconv.Atoi(val)
+}
+
+func parseFloat(val string) (any, error) {
+	return strconv.ParseFloat(val, 64)
+}
+
+func parsePosition(val string) (any, error) {
+	switch val {
+	case "absolute":
+		return PositionAbsolute, nil
+	case "static", "relative":
+		return PositionStatic, nil
+	}
+	return PositionStatic, fmt.Errorf("unknown position: %s", val)
+}
+
+func parseDirection(val string) (any, error) {
+	switch val {
+	case "row":
+		return Row, nil
+	case "column":
+		return Column, nil
+	}
+	return Column, fmt.Errorf("unknown direction: %s", val)
+}
+
+func parseWrap(val string) (any, error) {
+	switch val {
+	case "wrap":
+		return Wrap, nil
+	case "nowrap":
+		return NoWrap, nil
+	}
+	return NoWrap, fmt.Errorf("unknown wrap: %s", val)
+}
+
+func parseJustify(val string) (any, error) {
+	switch val {
+	case "flex-start", "start":
+		return JustifyStart, nil
+	case "flex-end", "end":
+		return JustifyEnd, nil
+	case "space-between":
+		return JustifySpaceBetween, nil
+	case "space-around":
+		return JustifySpaceAround, nil
+	case "center":
+		return JustifyCenter, nil
+	}
+	return JustifyStart, fmt.Errorf("unknown justify: %s", val)
+}
+
+func parseAlignItem(val string) (any, error) {
+	switch val {
+	case "flex-start", "start":
+		return AlignItemStart, nil
+	case "flex-end", "end":
+		return AlignItemEnd, nil
+	case "center":
+		return AlignItemCenter, nil
+	case "stretch":
+		return AlignItemStretch, nil
+	}
+	return AlignItemStretch, fmt.Errorf("unknown align-items: %s", val)
+}
+
+func parseAlignContent(val string) (any, error) {
+	switch val {
+	case "flex-start", "start":
+		return AlignContentStart, nil
+	case "flex-end", "end":
+		return AlignContentEnd, nil
+	case "center":
+		return AlignContentCenter, nil
+	case "stretch":
+		return AlignContentStretch, nil
+	case "space-between":
+		return AlignContentSpaceBetween, nil
+	case "space-around":
+		return AlignContentSpaceAround, nil
+	}
+	return AlignContentStart, fmt.Errorf("unknown align-content: %s", val)
+}
+
+func parseDisplay(val string) (any, error) {
+	switch val {
+	case "none":
+		return DisplayNone, nil
+	case "", "flex":
+		return DisplayFlex, nil
+	case "anchor":
+		return DisplayAnchor, nil
+	}
+	return DisplayFlex, fmt.Errorf("unknown display: %s", val)
+}
+
+// parseFraction parses a value such as `0.5` or `50%` into a fraction.
+func parseFraction(val string) (any, error) {
+	if strings.HasSuffix(val, "%") {
+		v, err := strconv.ParseFloat(strings.TrimSuffix(val, "%"), 64)
+		return v / 100, err
+	}
+	return strconv.ParseFloat(val, 64)
+}
+
+func parseAnchorPreset(val string) (any, error) {
+	switch val {
+	case "top-left":
+		return AnchorPresetTopLeft, nil
+	case "top":
+		return AnchorPresetTop, nil
+	case "top-right":
+		return AnchorPresetTopRight, nil
+	case "left":
+		return AnchorPresetLeft, nil
+	case "center":
+		return AnchorPresetCenter, nil
+	case "right":
+		return AnchorPresetRight, nil
+	case "bottom-left":
+		return AnchorPresetBottomLeft, nil
+	case "bottom":
+		return AnchorPresetBottom, nil
+	case "bottom-right":
+		return AnchorPresetBottomRight, nil
+	case "full":
+		return AnchorPresetFull, nil
+	}
+	return AnchorPresetTopLeft, fmt.Errorf("unknown anchor: %s", val)
+}
+
+// parseWillChange parses the CSS will-change property.
+// Any value other than `auto` enables the render cache of the view.
+func parseWillChange(val string) (any, error) {
+	return val != "auto", nil
+}
+
+func parseOpacity(val string) (any, error) {
+	v, err := parseFraction(val)
+	if err != nil {
+		return 1., err
+	}
+	return math.Max(0, math.Min(1, v.(float64))), nil
+}
+
+// parseURL parses `url(name)`, `url("name")` or a bare name.
+func parseURL(val string) (any, error) {
+	if val == "none" {
+		return "", nil
+	}
+	if strings.HasPrefix(val, "url(") && strings.HasSuffix(val, ")") {
+		val = strings.TrimSpace(val[len("url(") : len(val)-1])
+	}
+	return strings.Trim(val, `"'`), nil
+}
+
+// parseInsets parses one to four lengths in the order of top, right, bottom and left
+// like the margin shorthand.
+func parseInsets(val string) (any, error) {
+	var n []int
+	for _, f := range strings.Fields(val) {
+		v, err := parseNumber(f)
+		if err != nil {
+			return Insets{}, fmt.Errorf("invalid insets: %s", val)
+		}
+		n = append(n, v.(int))
+	}
+	switch len(n) {
+	case 1:
+		return Insets{n[0], n[0], n[0], n[0]}, nil
+	case 2:
+		return Insets{n[0], n[1], n[0], n[1]}, nil
+	case 3:
+		return Insets{n[0], n[1], n[2], n[1]}, nil
+	case 4:
+		return Insets{n[0], n[1], n[2], n[3]}, nil
+	}
+	return Insets{}, fmt.Errorf("invalid insets: %s", val)
+}
+
+func parseBackgroundSize(val string) (any, error) {
+	switch val {
+	case "stretch", "100% 100%":
+		return BackgroundSizeStretch, nil
+	case "tile", "auto", "repeat":
+		return BackgroundSizeTile, nil
+	case "contain":
+		return BackgroundSizeContain, nil
+	case "cover":
+		return BackgroundSizeCover, nil
+	}
+	return BackgroundSizeStretch, fmt.Errorf("unknown background-size: %s", val)
+}
+
+type cssBorder struct {
+	width int
+	color color.Color
+}
+
+// parseBorder parses the border shorthand such as `2px solid #fff`.
+func parseBorder(val string) (any, error) {
+	b := cssBorder{color: color.Black}
+	for _, f := range splitValues(val, ' ') {
+		switch f {
+		case "none", "hidden":
+			return cssBorder{}, nil
+		case "solid", "dashed", "dotted", "double":
+			continue
+		}
+		if w, err := parseNumber(f); err == nil {
+			b.width = w.(int)
+			continue
+		}
+		c, err := parseColor(f)
+		if err != nil {
+			return cssBorder{}, fmt.Errorf("invalid border: %s", val)
+		}
+		b.color = c.(color.Color)
+	}
+	return b, nil
+}
+
+// splitValues splits a CSS value by sep, ignoring the separators inside parentheses.
+// A space separator splits by any whitespace. The values are trimmed and empty values are dropped.
+func splitValues(val string, sep rune) []string {
+	var values []string
+	depth, start := 0, 0
+	add := func(end int) {
+		if v := strings.TrimSpace(val[start:end]); v != "" {
+			values = append(values, v)
+		}
+	}
+	for i, r := range val {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case depth == 0 && (r == sep || sep == ' ' && unicode.IsSpace(r)):
+			add(i)
+			start = i + 1
+		}
+	}
+	add(len(val))
+	return values
+}
+
+type cssLength struct {
+	unit cssUnit
+	val  float64
+}
+
+func parseLength(val string) (any, error) {
+	switch {
+	case strings.HasSuffix(val, "%"):
+		val = strings.TrimSuffix(val, "%")
+		v, err := parseFloat(val)
+		if err != nil || v.(float64) <= 0 {
+			return cssLength{}, nil
+		}
+		return cssLength{unit: cssUnitPct, val: v.(float64)}, nil
+	default:
+		val = strings.TrimSuffix(val, "px")
+		v, err := parseFloat(val)
+		if err != nil {
+			return cssLength{}, nil
+		}
+		return cssLength{unit: cssUnitPx, val: v.(float64)}, nil
+	}
+}
+
+type attrs struct {
+	id     string
+	style  string
+	hidden bool
+	miscs  map[string]string
+}
+
+func readAttrs(z *html.Tokenizer) attrs {
+	attr := attrs{
+		miscs: make(map[string]string),
+	}
+	for {
+		key, val, more := z.TagAttr()
+		attr.miscs[string(key)] = string(val)
+		switch string(key) {
+		case "id":
+			attr.id = string(val)
+		case "style":
+			attr.style = string(val)
+		case "hidden":
+			v := string(val)
+			if v == "" {
+				attr.hidden = true
+			} else {
+				attr.hidden = parseBool(v)
+			}
+		}
+		if !more {
+			break
+		}
+	}
+	return attr
+}
+
+func parseBool(val string) bool {
+	return val == "true"
+}
+
+type cssUnit int
+
+const (
+	cssUnitPx cssUnit = iota
+	cssUnitPct
+)
@@ -0,0 +1,214 @@
+package core
+
+import (
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+var registeredImages = map[string]Image{}
+
+// RegisterImage registers an image with a name so that views can use it
+// as `background-image` in CSS or BackgroundImage in Go.
+func RegisterImage(name string, img Image) {
+	registeredImages[name] = img
+}
+
+// subImager is an image that has sub images, such as *ebiten.Image and *image.RGBA.
+type subImager interface {
+	SubImage(r image.Rectangle) image.Image
+}
+
+// RegisterAtlas registers the sub images of an atlas image.
+// The key of rects is the name of each sub image.
+// The image must have a SubImage method like *ebiten.Image and *image.RGBA.
+func RegisterAtlas(img Image, rects map[string]image.Rectangle) {
+	s, ok := img.(subImager)
+	if !ok {
+		panic(fmt.Sprintf("core: the atlas image %T has no SubImage method", img))
+	}
+	for name, r := range rects {
+		RegisterImage(name, s.SubImage(r))
+	}
+}
+
+// LoadImages decodes the PNG or JPEG files at the given paths of fsys
+// and registers them. Each image is named after its file name without the extension,
+// e.g. `images/panel.png` is registered as `panel`.
+func LoadImages(fsys fs.FS, paths ...string) error {
+	for _, p := range paths {
+		f, err := fsys.Open(p)
+		if err != nil {
+			return err
+		}
+		img, _, err := image.Decode(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("decode image %s: %w", p, err)
+		}
+		name := strings.TrimSuffix(path.Base(p), path.Ext(p))
+		RegisterImage(name, img)
+	}
+	return nil
+}
+
+// GetImage returns the image registered with the name.
+func GetImage(name string) (Image, bool) {
+	img, ok := registeredImages[name]
+	return img, ok
+}
+
+func resetImages() { registeredImages = map[string]Image{} }
+
+// BackgroundSize is the 'background-size' property.
+// It controls how the background image fills the view.
+type BackgroundSize uint8
+
+const (
+	// BackgroundSizeStretch stretches the image to the frame.
+	// With a slice, the edges and the center are stretched.
+	BackgroundSizeStretch BackgroundSize = iota
+	// BackgroundSizeTile repeats the image in its own size.
+	// With a slice, the edges and the center are repeated.
+	BackgroundSizeTile
+	// BackgroundSizeContain scales the image to fit inside the frame, keeping the aspect ratio.
+	BackgroundSizeContain
+	// BackgroundSizeCover scales the image to cover the frame, keeping the aspect ratio.
+	BackgroundSizeCover
+)
+
+func (b BackgroundSize) String() string {
+	switch b {
+	case BackgroundSizeStretch:
+		return "stretch"
+	case BackgroundSizeTile:
+		return "tile"
+	case BackgroundSizeContain:
+		return "contain"
+	case BackgroundSizeCover:
+		return "cover"
+	}
+	return fmt.Sprintf("unknown background-size: %d", b)
+}
+
+// Insets represents the distances from each edge of a rectangle.
+type Insets struct {
+	Top, Right, Bottom, Left int
+}
+
+func (i Insets) isZero() bool { return i == Insets{} }
+
+// imagePart is a part of the source image to be drawn to the destination rectangle.
+type imagePart struct {
+	src image.Rectangle
+	dst image.Rectangle
+}
+
+// backgroundImageParts splits the source image into the parts to draw into dst.
+// The slice insets are in source pixels and the scale converts them to dst pixels.
+func backgroundImageParts(src, dst image.Rectangle, slice Insets, size BackgroundSize, scale float64) []imagePart {
+	if slice.isZero() {
+		switch size {
+		case BackgroundSizeTile:
+			return tileParts(src, dst, scale)
+		case BackgroundSizeContain, BackgroundSizeCover:
+			return []imagePart{{src: src, dst: fitRect(src.Size(), dst, size == BackgroundSizeCover)}}
+		}
+		return []imagePart{{src: src, dst: dst}}
+	}
+
+	s := func(v int) int { return round(float64(v) * scale) }
+	sx := [4]int{src.Min.X, src.Min.X + slice.Left, src.Max.X - slice.Right, src.Max.X}
+	sy := [4]int{src.Min.Y, src.Min.Y + slice.Top, src.Max.Y - slice.Bottom, src.Max.Y}
+	dx := [4]int{dst.Min.X, dst.Min.X + s(slice.Left), dst.Max.X - s(slice.Right), dst.Max.X}
+	dy := [4]int{dst.Min.Y, dst.Min.Y + s(slice.Top), dst.Max.Y - s(slice.Bottom), dst.Max.Y}
+
+	var parts []imagePart
+	for j := 0; j < 3; j++ {
+		for i := 0; i < 3; i++ {
+			sr := image.Rect(sx[i], sy[j], sx[i+1], sy[j+1])
+			dr := image.Rect(dx[i], dy[j], dx[i+1], dy[j+1])
+			if sr.Empty() || dr.Empty() {
+				continue
+			}
+			// The corners are never repeated.
+			if size == BackgroundSizeTile && (i == 1 || j == 1) {
+				parts = append(parts, tilePartsAxis(sr, dr, scale, i == 1, j == 1)...)
+				continue
+			}
+			parts = append(parts, imagePart{src: sr, dst: dr})
+		}
+	}
+	return parts
+}
+
+func tileParts(src, dst image.Rectangle, scale float64) []imagePart {
+	return tilePartsAxis(src, dst, scale, true, true)
+}
+
+// tilePartsAxis repeats src over dst along the given axes.
+// Along an axis that is not repeated, src is stretched.
+func tilePartsAxis(src, dst image.Rectangle, scale float64, repeatX, repeatY bool) []imagePart {
+	tw, th := round(float64(src.Dx())*scale), round(float64(src.Dy())*scale)
+	if !repeatX || tw <= 0 {
+		tw = dst.Dx()
+	}
+	if !repeatY || th <= 0 {
+		th = dst.Dy()
+	}
+	var parts []imagePart
+	for y := dst.Min.Y; y < dst.Max.Y; y += th {
+		for x := dst.Min.X; x < dst.Max.X; x += tw {
+			dr := image.Rect(x, y, x+tw, y+th).Intersect(dst)
+			sr := src
+			// Cut the source of the last tiles so that they are not squashed.
+			if repeatX && dr.Dx() < tw {
+				sr.Max.X = sr.Min.X + round(float64(dr.Dx())/scale)
+			}
+			if repeatY && dr.Dy() < th {
+				sr.Max.Y = sr.Min.Y + round(float64(dr.Dy())/scale)
+			}
+			if sr.Empty() {
+				continue
+			}
+			parts = append(parts, imagePart{src: sr, dst: dr})
+		}
+	}
+	return parts
+}
+
+// fitRect returns the rectangle of the given size scaled to fit (or to cover) dst,
+// centered in dst.
+func fitRect(size image.Point, dst image.Rectangle, cover bool) image.Rectangle {
+	if size.X == 0 || size.Y == 0 {
+		return dst
+	}
+	sx, sy := float64(dst.Dx())/float64(size.X), float64(dst.Dy())/float64(size.Y)
+	s := sx
+	if (cover && sy > sx) || (!cover && sy < sx) {
+		s = sy
+	}
+	w, h := round(float64(size.X)*s), round(float64(size.Y)*s)
+	x, y := dst.Min.X+(dst.Dx()-w)/2, dst.Min.Y+(dst.Dy()-h)/2
+	return image.Rect(x, y, x+w, y+h)
+}
+
+// drawBackgroundImage draws the background image of the view inside the frame.
+func (v *View) drawBackgroundImage(r Renderer, frame image.Rectangle) {
+	img, ok := GetImage(v.BackgroundImage)
+	if !ok {
+		return
+	}
+	if v.BackgroundSize == BackgroundSizeCover {
+		r.PushClip(frame)
+		defer r.PopClip()
+	}
+	parts := backgroundImageParts(img.Bounds(), frame, v.BackgroundSlice, v.BackgroundSize, v.UIScale())
+	for _, p := range parts {
+		r.DrawImage(img, p.src, p.dst, nil)
+	}
+}
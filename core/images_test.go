@@ -0,0 +1,129 @@
+package core
+
+import (
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterImage(t *testing.T) {
+	resetImages()
+	defer resetImages()
+
+	img := image.NewRGBA(image.Rect(0, 0, 64, 32))
+	RegisterImage("sheet", img)
+	RegisterAtlas(img, map[string]image.Rectangle{
+		"panel":  image.Rect(0, 0, 32, 32),
+		"button": image.Rect(32, 0, 64, 16),
+	})
+
+	got, ok := GetImage("sheet")
+	require.True(t, ok)
+	require.Same(t, img, got)
+
+	got, ok = GetImage("button")
+	require.True(t, ok)
+	require.Equal(t, image.Rect(32, 0, 64, 16), got.Bounds())
+
+	_, ok = GetImage("unknown")
+	require.False(t, ok)
+}
+
+func TestBackgroundImageParts(t *testing.T) {
+	src := image.Rect(10, 10, 40, 40)
+	dst := image.Rect(0, 0, 100, 60)
+	slice := Insets{Top: 5, Right: 10, Bottom: 5, Left: 10}
+
+	t.Run("stretch", func(t *testing.T) {
+		parts := backgroundImageParts(src, dst, Insets{}, BackgroundSizeStretch, 1)
+		require.Equal(t, []imagePart{{src: src, dst: dst}}, parts)
+	})
+
+	t.Run("nine-slice", func(t *testing.T) {
+		parts := backgroundImageParts(src, dst, slice, BackgroundSizeStretch, 1)
+		require.Len(t, parts, 9)
+		// top left
+		require.Equal(t, imagePart{src: image.Rect(10, 10, 20, 15), dst: image.Rect(0, 0, 10, 5)}, parts[0])
+		// center
+		require.Equal(t, imagePart{src: image.Rect(20, 15, 30, 35), dst: image.Rect(10, 5, 90, 55)}, parts[4])
+		// bottom right
+		require.Equal(t, imagePart{src: image.Rect(30, 35, 40, 40), dst: image.Rect(90, 55, 100, 60)}, parts[8])
+	})
+
+	t.Run("nine-slice scaled", func(t *testing.T) {
+		parts := backgroundImageParts(src, dst, slice, BackgroundSizeStretch, 2)
+		require.Equal(t, image.Rect(0, 0, 20, 10), parts[0].dst)
+		require.Equal(t, image.Rect(20, 10, 80, 50), parts[4].dst)
+	})
+
+	t.Run("nine-slice tile", func(t *testing.T) {
+		parts := backgroundImageParts(src, dst, slice, BackgroundSizeTile, 1)
+		var top []imagePart
+		for _, p := range parts {
+			if p.dst.Min.Y == 0 && p.dst.Min.X >= 10 && p.dst.Max.X <= 90 {
+				top = append(top, p)
+			}
+		}
+		// The top edge is 80px wide and the source is 10px wide.
+		require.Len(t, top, 8)
+		for _, p := range top {
+			require.Equal(t, image.Rect(20, 10, 30, 15), p.src)
+			require.Equal(t, 10, p.dst.Dx())
+			require.Equal(t, 5, p.dst.Dy())
+		}
+	})
+
+	t.Run("tile", func(t *testing.T) {
+		parts := backgroundImageParts(src, image.Rect(0, 0, 70, 30), Insets{}, BackgroundSizeTile, 1)
+		require.Len(t, parts, 3)
+		require.Equal(t, imagePart{src: src, dst: image.Rect(0, 0, 30, 30)}, parts[0])
+		require.Equal(t, imagePart{src: image.Rect(10, 10, 20, 40), dst: image.Rect(60, 0, 70, 30)}, parts[2])
+	})
+
+	t.Run("contain", func(t *testing.T) {
+		parts := backgroundImageParts(src, dst, Insets{}, BackgroundSizeContain, 1)
+		require.Equal(t, []imagePart{{src: src, dst: image.Rect(20, 0, 80, 60)}}, parts)
+	})
+
+	t.Run("cover", func(t *testing.T) {
+		parts := backgroundImageParts(src, dst, Insets{}, BackgroundSizeCover, 1)
+		require.Equal(t, []imagePart{{src: src, dst: image.Rect(0, -20, 100, 80)}}, parts)
+	})
+}
+
+func TestParseBackgroundImage(t *testing.T) {
+	resetComponents()
+	v := Parse(`
+		<view style="background-image: url('panel'); background-slice: 8 12; background-size: tile">
+			<view id="child" style="background-image: button; background-slice: 4; background-size: cover"></view>
+		</view>`, nil)
+
+	require.Equal(t, "panel", v.BackgroundImage)
+	require.Equal(t, Insets{Top: 8, Right: 12, Bottom: 8, Left: 12}, v.BackgroundSlice)
+	require.Equal(t, BackgroundSizeTile, v.BackgroundSize)
+	require.True(t, v.hasBox())
+
+	child := v.MustGetByID("child")
+	require.Equal(t, "button", child.BackgroundImage)
+	require.Equal(t, Insets{4, 4, 4, 4}, child.BackgroundSlice)
+	require.Equal(t, BackgroundSizeCover, child.BackgroundSize)
+}
+
+func TestParseInsets(t *testing.T) {
+	for _, tt := range []struct {
+		val  string
+		want Insets
+	}{
+		{val: "1", want: Insets{1, 1, 1, 1}},
+		{val: "1 2", want: Insets{1, 2, 1, 2}},
+		{val: "1px 2px 3px", want: Insets{1, 2, 3, 2}},
+		{val: "1 2 3 4", want: Insets{1, 2, 3, 4}},
+	} {
+		v, err := parseInsets(tt.val)
+		require.NoError(t, err)
+		require.Equal(t, tt.want, v)
+	}
+	_, err := parseInsets("1 2 3 4 5")
+	require.Error(t, err)
+}
@@ -0,0 +1,126 @@
+package core
+
+// TouchID is the ID of a touch.
+type TouchID int
+
+// MouseButton is a button of the mouse.
+type MouseButton int
+
+const (
+	MouseButtonLeft MouseButton = iota
+	MouseButtonRight
+	MouseButtonMiddle
+	mouseButtonCount
+)
+
+// Key is a key of the keyboard.
+type Key int
+
+const (
+	KeyUnknown Key = iota
+	KeyArrowUp
+	KeyArrowDown
+	KeyArrowLeft
+	KeyArrowRight
+	KeyShift
+	KeyControl
+	KeyAlt
+	KeyEnter
+	KeyEscape
+	KeyTab
+	KeySpace
+	KeyBackspace
+	KeyF1
+	KeyF2
+	KeyF3
+	KeyF4
+	KeyF5
+	KeyF6
+	KeyF7
+	KeyF8
+	KeyF9
+	KeyF10
+	KeyF11
+	KeyF12
+	KeyA
+	KeyB
+	KeyC
+	KeyD
+	KeyE
+	KeyF
+	KeyG
+	KeyH
+	KeyI
+	KeyJ
+	KeyK
+	KeyL
+	KeyM
+	KeyN
+	KeyO
+	KeyP
+	KeyQ
+	KeyR
+	KeyS
+	KeyT
+	KeyU
+	KeyV
+	KeyW
+	KeyX
+	KeyY
+	KeyZ
+	Key0
+	Key1
+	Key2
+	Key3
+	Key4
+	Key5
+	Key6
+	Key7
+	Key8
+	Key9
+	keyCount
+)
+
+// Input is the state of the mouse, the touches and the keyboard in the current frame.
+// Positions are in screen pixels. The engine adapter sets it with SetInput.
+type Input interface {
+	// CursorPosition returns the position of the mouse cursor.
+	CursorPosition() (x, y int)
+	// Wheel returns the scroll of the mouse wheel in this frame.
+	Wheel() (x, y float64)
+	IsMouseButtonPressed(b MouseButton) bool
+	IsMouseButtonJustPressed(b MouseButton) bool
+	IsMouseButtonJustReleased(b MouseButton) bool
+	// AppendJustPressedTouchIDs appends the touches that started in this frame to ids.
+	AppendJustPressedTouchIDs(ids []TouchID) []TouchID
+	IsTouchJustReleased(id TouchID) bool
+	// TouchPosition returns the position of a touch that is not released.
+	TouchPosition(id TouchID) (x, y int)
+	IsKeyPressed(k Key) bool
+	IsKeyJustPressed(k Key) bool
+}
+
+var input Input = noInput{}
+
+// SetInput sets the input that the view trees read events from.
+// Passing nil removes the input: no events happen.
+func SetInput(in Input) {
+	if in == nil {
+		in = noInput{}
+	}
+	input = in
+}
+
+// noInput is the input when there is no engine: the cursor is outside and nothing is pressed.
+type noInput struct{}
+
+func (noInput) CursorPosition() (int, int)                        { return -1, -1 }
+func (noInput) Wheel() (float64, float64)                         { return 0, 0 }
+func (noInput) IsMouseButtonPressed(MouseButton) bool             { return false }
+func (noInput) IsMouseButtonJustPressed(MouseButton) bool         { return false }
+func (noInput) IsMouseButtonJustReleased(MouseButton) bool        { return false }
+func (noInput) AppendJustPressedTouchIDs(ids []TouchID) []TouchID { return ids }
+func (noInput) IsTouchJustReleased(TouchID) bool                  { return false }
+func (noInput) TouchPosition(TouchID) (int, int)                  { return 0, 0 }
+func (noInput) IsKeyPressed(Key) bool                             { return false }
+func (noInput) IsKeyJustPressed(Key) bool                         { return false }
@@ -0,0 +1,547 @@
+package core
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+
+	"golang.org/x/image/font/basicfont"
+)
+
+// Inspector is an overlay that shows the view tree, the boxes of the hovered view
+// and the frame, style and attributes of the selected view, and edits its layout
+// properties while the game is running.
+//
+// Call Update instead of the Update of the root view and Draw after drawing the root view.
+// While the inspector is shown, the views do not receive input events: hovering a view
+// highlights it and clicking selects it. In the panel, clicking a `+` or `-` expands or
+// collapses a view, Up and Down choose a layout property, and Left and Right change it,
+// by 10 while Shift is pressed.
+type Inspector struct {
+	// ToggleKey shows and hides the inspector.
+	ToggleKey Key
+	// Enabled is true while the inspector is shown.
+	Enabled bool
+	// PanelWidth is the width of the panel in screen pixels.
+	PanelWidth int
+
+	root      *View
+	hovered   *View
+	selected  *View
+	collapsed map[*View]bool
+	field     int
+	scroll    int
+	rows      []inspectorRow
+	size      image.Point
+	renderer  ScreenRenderer
+}
+
+const (
+	inspectorRowHeight = 14
+	inspectorCharWidth = 7
+	inspectorPadding   = 4
+)
+
+var (
+	inspectorPanelColor    = color.RGBA{0x20, 0x20, 0x24, 0xe8}
+	inspectorTextColor     = color.RGBA{0xe0, 0xe0, 0xe0, 0xff}
+	inspectorDimColor      = color.RGBA{0x80, 0x80, 0x88, 0xff}
+	inspectorHeaderColor   = color.RGBA{0x8a, 0xb4, 0xf8, 0xff}
+	inspectorRowColor      = color.RGBA{0x3a, 0x5a, 0x8a, 0xff}
+	inspectorMarginColor   = color.RGBA{0xf6, 0xb2, 0x6b, 0x80}
+	inspectorBorderColor   = color.RGBA{0xfd, 0xdd, 0x9b, 0x80}
+	inspectorContentColor  = color.RGBA{0x8c, 0xb6, 0xc0, 0x80}
+	inspectorSelectedColor = color.RGBA{0xff, 0x00, 0xff, 0xff}
+)
+
+// NewInspector creates an inspector of the view tree of root. It is toggled with F12.
+func NewInspector(root *View) *Inspector {
+	return &Inspector{
+		ToggleKey:  KeyF12,
+		PanelWidth: 320,
+		root:       root,
+		collapsed:  map[*View]bool{},
+	}
+}
+
+// inspectorField is a layout property that can be edited in the inspector.
+type inspectorField struct {
+	name   string
+	get    func(v *View) float64
+	set    func(v *View, val float64)
+	step   float64
+	signed bool
+}
+
+func intField(name string, signed bool, f func(v *View) *int) inspectorField {
+	return inspectorField{
+		name:   name,
+		get:    func(v *View) float64 { return float64(*f(v)) },
+		set:    func(v *View, val float64) { *f(v) = int(math.Round(val)) },
+		step:   1,
+		signed: signed,
+	}
+}
+
+func floatField(name string, f func(v *View) *float64) inspectorField {
+	return inspectorField{
+		name: name,
+		get:  func(v *View) float64 { return *f(v) },
+		set:  func(v *View, val float64) { *f(v) = math.Round(val*10) / 10 },
+		step: .1,
+	}
+}
+
+var inspectorFields = []inspectorField{
+	intField("left", true, func(v *View) *int { return &v.Left }),
+	intField("top", true, func(v *View) *int { return &v.Top }),
+	intField("width", false, func(v *View) *int { return &v.Width }),
+	intField("height", false, func(v *View) *int { return &v.Height }),
+	intField("margin-left", true, func(v *View) *int { return &v.MarginLeft }),
+	intField("margin-top", true, func(v *View) *int { return &v.MarginTop }),
+	intField("margin-right", true, func(v *View) *int { return &v.MarginRight }),
+	intField("margin-bottom", true, func(v *View) *int { return &v.MarginBottom }),
+	floatField("flex-grow", func(v *View) *float64 { return &v.Grow }),
+	floatField("flex-shrink", func(v *View) *float64 { return &v.Shrink }),
+	intField("border-width", false, func(v *View) *int { return &v.BorderWidth }),
+	intField("font-size", false, func(v *View) *int { return &v.FontSize }),
+}
+
+// inspectorRow is a line of the panel.
+type inspectorRow struct {
+	text  string
+	color color.Color
+	// view and depth are set for the rows of the view tree.
+	view  *View
+	depth int
+	// field is the index of an editable property plus one, or 0.
+	field int
+}
+
+// Update updates the root view, and handles the input of the inspector while it is shown.
+func (i *Inspector) Update() {
+	if input.IsKeyJustPressed(i.ToggleKey) {
+		i.Enabled = !i.Enabled
+	}
+	if !i.Enabled {
+		i.root.Update()
+		return
+	}
+	i.root.update(false)
+
+	x, y := input.CursorPosition()
+	i.hover(x, y)
+	if input.IsMouseButtonJustPressed(MouseButtonLeft) {
+		i.click(x, y)
+	}
+	if _, dy := input.Wheel(); dy != 0 {
+		i.scrollBy(-int(math.Copysign(3, dy)))
+	}
+	step := 1.
+	if input.IsKeyPressed(KeyShift) {
+		step = 10
+	}
+	switch {
+	case input.IsKeyJustPressed(KeyArrowUp):
+		i.field = (i.field + len(inspectorFields) - 1) % len(inspectorFields)
+	case input.IsKeyJustPressed(KeyArrowDown):
+		i.field = (i.field + 1) % len(inspectorFields)
+	case input.IsKeyJustPressed(KeyArrowLeft):
+		i.edit(-step)
+	case input.IsKeyJustPressed(KeyArrowRight):
+		i.edit(step)
+	}
+}
+
+// UpdateWithSize is the UpdateWithSize of the root view for the inspector.
+func (i *Inspector) UpdateWithSize(width, height int) {
+	i.root.setSize(width, height)
+	i.Update()
+}
+
+// Draw draws the inspector over the screen if it is shown.
+func (i *Inspector) Draw(screen Image) {
+	if !i.Enabled {
+		return
+	}
+	if i.renderer == nil {
+		i.renderer = newScreenRenderer()
+	}
+	i.renderer.Reset(screen)
+	i.render(i.renderer, screen.Bounds().Size())
+}
+
+// render draws the inspector over a screen of the size.
+func (i *Inspector) render(r Renderer, size image.Point) {
+	i.size = size
+	i.rows = i.buildRows()
+	i.scrollBy(0)
+
+	if v := i.hovered; v != nil {
+		drawTransformed(r, v, func() { i.drawBoxes(r, v) })
+	}
+	if v := i.selected; v != nil {
+		drawTransformed(r, v, func() {
+			r.StrokeRect(toPhysicalRect(v.frame, v.UIScale()), 0, 1, inspectorSelectedColor)
+		})
+	}
+	panel := i.panel()
+	r.FillRect(panel, 0, inspectorPanelColor)
+	for n, row := range i.visibleRows() {
+		if row.view != nil && (row.view == i.selected || row.view == i.hovered) ||
+			row.field != 0 && row.field-1 == i.field {
+			r.FillRect(image.Rect(panel.Min.X, n*inspectorRowHeight, panel.Max.X, (n+1)*inspectorRowHeight),
+				0, inspectorRowColor)
+		}
+	}
+
+	face := basicfont.Face7x13
+	ascent := face.Metrics().Ascent.Ceil()
+	maxChars := (panel.Dx() - inspectorPadding*2) / inspectorCharWidth
+	for n, row := range i.visibleRows() {
+		s := row.text
+		if s == "" {
+			continue
+		}
+		if len(s) > maxChars {
+			s = s[:maxChars]
+		}
+		r.DrawText(s, face, panel.Min.X+inspectorPadding, n*inspectorRowHeight+ascent, row.color)
+	}
+}
+
+// drawTransformed calls draw to draw over the view in its untransformed pixels.
+// The drawing is transformed like the view through a layer if the view or an ancestor
+// is transformed or moved by a layout animation.
+func drawTransformed(r Renderer, v *View, draw func()) {
+	g, ok := v.screenGeoM()
+	if !ok {
+		draw()
+		return
+	}
+	r.BeginLayer()
+	draw()
+	r.EndLayer(&LayerOptions{GeoM: g, Alpha: 1})
+}
+
+// drawBoxes highlights the margin, the border and the content boxes of the view.
+func (i *Inspector) drawBoxes(r Renderer, v *View) {
+	scale := v.UIScale()
+	f := toPhysicalRect(v.frame, scale)
+	// The rectangles are not canonicalized, so negative margins are not drawn.
+	rect := func(x0, y0, x1, y1 int, clr color.Color) {
+		if x0 < x1 && y0 < y1 {
+			r.FillRect(image.Rect(x0, y0, x1, y1), 0, clr)
+		}
+	}
+	m := image.Rectangle{
+		Min: image.Pt(f.Min.X-int(float64(v.MarginLeft)*scale), f.Min.Y-int(float64(v.MarginTop)*scale)),
+		Max: image.Pt(f.Max.X+int(float64(v.MarginRight)*scale), f.Max.Y+int(float64(v.MarginBottom)*scale)),
+	}
+	rect(m.Min.X, m.Min.Y, m.Max.X, f.Min.Y, inspectorMarginColor)
+	rect(m.Min.X, f.Max.Y, m.Max.X, m.Max.Y, inspectorMarginColor)
+	rect(m.Min.X, f.Min.Y, f.Min.X, f.Max.Y, inspectorMarginColor)
+	rect(f.Max.X, f.Min.Y, m.Max.X, f.Max.Y, inspectorMarginColor)
+	bw := float32(math.Round(float64(v.BorderWidth) * scale))
+	r.StrokeRect(f, 0, bw, inspectorBorderColor)
+	if bw*2 < float32(f.Dx()) && bw*2 < float32(f.Dy()) {
+		c := f.Inset(int(bw))
+		rect(c.Min.X, c.Min.Y, c.Max.X, c.Max.Y, inspectorContentColor)
+	}
+}
+
+func (i *Inspector) panel() image.Rectangle {
+	return image.Rect(i.size.X-i.PanelWidth, 0, i.size.X, i.size.Y)
+}
+
+func (i *Inspector) visibleRows() []inspectorRow {
+	n := i.size.Y / inspectorRowHeight
+	rows := i.rows[i.scroll:]
+	if len(rows) > n {
+		rows = rows[:n]
+	}
+	return rows
+}
+
+func (i *Inspector) scrollBy(d int) {
+	i.scroll += d
+	if max := len(i.rows) - i.size.Y/inspectorRowHeight; i.scroll > max {
+		i.scroll = max
+	}
+	if i.scroll < 0 {
+		i.scroll = 0
+	}
+}
+
+// rowAt returns the row at the point in screen pixels, or nil.
+func (i *Inspector) rowAt(x, y int) *inspectorRow {
+	if !image.Pt(x, y).In(i.panel()) {
+		return nil
+	}
+	n := y/inspectorRowHeight + i.scroll
+	if n < 0 || n >= len(i.rows) {
+		return nil
+	}
+	return &i.rows[n]
+}
+
+// hover sets the hovered view to the view of the tree row or the view under the point.
+func (i *Inspector) hover(x, y int) {
+	if image.Pt(x, y).In(i.panel()) {
+		i.hovered = nil
+		if row := i.rowAt(x, y); row != nil {
+			i.hovered = row.view
+		}
+		return
+	}
+	i.hovered = i.screenViewAt(x, y)
+}
+
+// click selects a view, expands or collapses a tree row, or chooses a property.
+func (i *Inspector) click(x, y int) {
+	if !image.Pt(x, y).In(i.panel()) {
+		i.selected = i.screenViewAt(x, y)
+		return
+	}
+	row := i.rowAt(x, y)
+	switch {
+	case row == nil:
+	case row.view != nil:
+		marker := i.panel().Min.X + inspectorPadding + row.depth*2*inspectorCharWidth
+		if x >= marker && x < marker+inspectorCharWidth && len(row.view.children) > 0 {
+			i.collapsed[row.view] = !i.collapsed[row.view]
+			return
+		}
+		i.selected = row.view
+	case row.field != 0:
+		i.field = row.field - 1
+	}
+}
+
+// edit changes the chosen property of the selected view by the number of steps.
+func (i *Inspector) edit(steps float64) {
+	v := i.selected
+	if v == nil {
+		return
+	}
+	f := inspectorFields[i.field]
+	val := f.get(v) + f.step*steps
+	if !f.signed && val < 0 {
+		val = 0
+	}
+	f.set(v, val)
+	v.Layout()
+}
+
+// viewAt returns the deepest visible view drawn at the point in logical pixels
+// in the coordinate space of the view's parent, like the hit test of the events.
+func (v *View) viewAt(x, y int) *View {
+	if v.Hidden || v.Display == DisplayNone {
+		return nil
+	}
+	x, y = v.toLocal(x, y)
+	for c := len(v.children) - 1; c >= 0; c-- {
+		if found := v.children[c].item.viewAt(x, y); found != nil {
+			return found
+		}
+	}
+	if image.Pt(x, y).In(v.frame) {
+		return v
+	}
+	return nil
+}
+
+// screenViewAt returns the view of the tree drawn at the point in screen pixels.
+func (i *Inspector) screenViewAt(x, y int) *View {
+	return i.root.viewAt(toLogicalPoint(x, y, i.root.UIScale()))
+}
+
+func (i *Inspector) buildRows() []inspectorRow {
+	rows := []inspectorRow{{text: "Views", color: inspectorHeaderColor}}
+	rows = i.appendTree(rows, i.root, 0)
+	v := i.selected
+	if v == nil {
+		v = i.hovered
+	}
+	if v == nil {
+		return append(rows, inspectorRow{text: "Hover or click a view to inspect it", color: inspectorDimColor})
+	}
+	rows = append(rows, inspectorRow{})
+	rows = append(rows, inspectorRow{text: viewLabel(v), color: inspectorHeaderColor})
+	f := v.frame
+	rows = append(rows,
+		inspectorRow{text: fmt.Sprintf("frame   (%d, %d)-(%d, %d) %dx%d",
+			f.Min.X, f.Min.Y, f.Max.X, f.Max.Y, f.Dx(), f.Dy()), color: inspectorTextColor},
+		inspectorRow{text: fmt.Sprintf("margin  %d %d %d %d",
+			v.MarginTop, v.MarginRight, v.MarginBottom, v.MarginLeft), color: inspectorTextColor},
+	)
+
+	rows = append(rows, inspectorRow{}, inspectorRow{text: "Layout (Up/Down, Left/Right)", color: inspectorHeaderColor})
+	for n, f := range inspectorFields {
+		rows = append(rows, inspectorRow{
+			text:  fmt.Sprintf("%s: %v", f.name, f.get(v)),
+			color: inspectorTextColor,
+			field: n + 1,
+		})
+	}
+
+	rows = append(rows, inspectorRow{}, inspectorRow{text: "Style", color: inspectorHeaderColor})
+	for _, s := range styleValues(v.saveStyle()) {
+		rows = append(rows, inspectorRow{text: s, color: inspectorTextColor})
+	}
+
+	if decls := v.cssDeclarations(); len(decls) > 0 {
+		rows = append(rows, inspectorRow{}, inspectorRow{text: "CSS", color: inspectorHeaderColor})
+		for _, d := range decls {
+			clr := color.Color(inspectorTextColor)
+			if d.overridden {
+				clr = inspectorDimColor
+			}
+			rows = append(rows, inspectorRow{text: fmt.Sprintf("%s: %s  %s", d.property, d.value, d.origin), color: clr})
+		}
+	}
+
+	if len(v.Attrs) > 0 {
+		rows = append(rows, inspectorRow{}, inspectorRow{text: "Attributes", color: inspectorHeaderColor})
+		keys := make([]string, 0, len(v.Attrs))
+		for k := range v.Attrs {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			rows = append(rows, inspectorRow{text: fmt.Sprintf("%s=%q", k, v.Attrs[k]), color: inspectorTextColor})
+		}
+	}
+	return rows
+}
+
+func (i *Inspector) appendTree(rows []inspectorRow, v *View, depth int) []inspectorRow {
+	marker := " "
+	if len(v.children) > 0 {
+		marker = "-"
+		if i.collapsed[v] {
+			marker = "+"
+		}
+	}
+	clr := color.Color(inspectorTextColor)
+	if v.Hidden || v.Display == DisplayNone {
+		clr = inspectorDimColor
+	}
+	rows = append(rows, inspectorRow{
+		text:  strings.Repeat("  ", depth) + marker + " " + viewLabel(v),
+		color: clr,
+		view:  v,
+		depth: depth,
+	})
+	if i.collapsed[v] {
+		return rows
+	}
+	for _, c := range v.children {
+		rows = i.appendTree(rows, c.item, depth+1)
+	}
+	return rows
+}
+
+// viewLabel returns a label such as `div#menu.panel.dark`.
+func viewLabel(v *View) string {
+	label := v.TagName
+	if label == "" {
+		label = "view"
+	}
+	if v.ID != "" {
+		label += "#" + v.ID
+	}
+	for _, c := range strings.Fields(v.Attrs["class"]) {
+		label += "." + c
+	}
+	return label
+}
+
+// styleValues returns the fields of the style that are not zero.
+func styleValues(s viewStyle) []string {
+	var values []string
+	rv := reflect.ValueOf(s)
+	for n := 0; n < rv.NumField(); n++ {
+		f := rv.Field(n)
+		if f.IsZero() {
+			continue
+		}
+		if f.Kind() == reflect.Ptr {
+			f = f.Elem()
+		}
+		values = append(values, fmt.Sprintf("%s: %v", rv.Type().Field(n).Name, f.Interface()))
+	}
+	return values
+}
+
+// cssDeclaration is a property set by the style of a parsed view.
+type cssDeclaration struct {
+	property, value string
+	// origin is `style`, the @media rule or `SetStyle` that set the property.
+	origin string
+	// overridden is true if a later declaration sets the same property.
+	overridden bool
+}
+
+// cssDeclarations returns the declarations that apply to the view: the style, the @media rules
+// that match the size of the root view and win over the style, then those set with SetStyle.
+func (v *View) cssDeclarations() []cssDeclaration {
+	if v.css == nil {
+		return nil
+	}
+	var decls []cssDeclaration
+	// add adds the declarations of style except those with the same value in skip.
+	add := func(style, origin string, skip map[string]string) {
+		for _, pair := range strings.Split(style, ";") {
+			kv := strings.SplitN(pair, ":", 2)
+			if len(kv) != 2 {
+				continue
+			}
+			d := cssDeclaration{
+				property: strings.TrimSpace(kv[0]),
+				value:    strings.TrimSpace(kv[1]),
+				origin:   origin,
+			}
+			if val, ok := skip[d.property]; !ok || val != d.value {
+				decls = append(decls, d)
+			}
+		}
+	}
+	add(v.css.base, "style", nil)
+	if s := v.css.sheet; s != nil {
+		root := v
+		for root.hasParent {
+			root = root.parent
+		}
+		key := s.key(root.Width, root.Height)
+		base := styleDeclarations(v.css.base)
+		for n, b := range s.blocks {
+			if key[n] != '1' {
+				continue
+			}
+			// The rules of the block alone show the declarations that it gives to the view.
+			styles := s.stylesOf(strings.Repeat("0", n) + "1" + strings.Repeat("0", len(key)-n-1))
+			if styles == nil {
+				continue
+			}
+			add(styles[v.css.index], "@media "+b.text, base)
+		}
+	}
+	add(v.css.style, "SetStyle", nil)
+	// A declaration is in effect if it has the value of the cascade and no later one has it.
+	values := styleDeclarations(mergeStyle(v.css.applied, v.css.style))
+	for n, d := range decls {
+		decls[n].overridden = d.value != values[d.property]
+		for _, later := range decls[n+1:] {
+			if later.property == d.property && later.value == values[d.property] {
+				decls[n].overridden = true
+				break
+			}
+		}
+	}
+	return decls
+}
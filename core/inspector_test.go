@@ -0,0 +1,159 @@
+package core
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func setupInspector(t *testing.T) (*Inspector, *View) {
+	resetComponents()
+	v := Parse(`<html><head><style>
+		#box { width: 100; }
+		@media (max-width: 600px) { #box { width: 50; } }
+		</style></head><body>
+		<view style="width: 400; height: 300; align-items: flex-start">
+			<view id="box" class="panel dark" data-x="1" style="height: 40; margin-left: 10">
+				<view id="inner" style="width: 10; height: 10"></view>
+			</view>
+			<view id="other" style="width: 20; height: 20"></view>
+		</view></body></html>`, nil)
+	v.Draw(image.NewRGBA(image.Rect(0, 0, 400, 300)))
+	i := NewInspector(v)
+	i.Enabled = true
+	i.Draw(image.NewRGBA(image.Rect(0, 0, 800, 300)))
+	return i, v
+}
+
+func rowTexts(rows []inspectorRow) []string {
+	var ss []string
+	for _, r := range rows {
+		ss = append(ss, r.text)
+	}
+	return ss
+}
+
+func TestInspectorHover(t *testing.T) {
+	i, v := setupInspector(t)
+	box := v.MustGetByID("box")
+	require.Equal(t, 50, box.frame.Dx())
+
+	i.hover(box.frame.Min.X+1, box.frame.Min.Y+1)
+	require.Same(t, v.MustGetByID("inner"), i.hovered)
+	i.hover(box.frame.Min.X+30, box.frame.Min.Y+30)
+	require.Same(t, box, i.hovered)
+	i.hover(399, 299)
+	require.Same(t, v, i.hovered)
+	i.hover(450, 10)
+	require.Nil(t, i.hovered)
+
+	// Hovering a row of the tree hovers its view.
+	i.hover(500, 2*inspectorRowHeight+1)
+	require.Same(t, box, i.hovered)
+}
+
+func TestInspectorTree(t *testing.T) {
+	i, v := setupInspector(t)
+	require.Equal(t, []string{
+		"Views",
+		"- view",
+		"  - view#box.panel.dark",
+		"      view#inner",
+		"    view#other",
+	}, rowTexts(i.rows[:5]))
+
+	// Clicking the marker collapses the view.
+	marker := i.panel().Min.X + inspectorPadding + 2*inspectorCharWidth
+	i.click(marker+1, 2*inspectorRowHeight+1)
+	i.Draw(image.NewRGBA(image.Rect(0, 0, 800, 300)))
+	require.Equal(t, "  + view#box.panel.dark", i.rows[2].text)
+	require.Equal(t, "    view#other", i.rows[3].text)
+	require.Nil(t, i.selected)
+
+	// Clicking the label selects the view.
+	i.click(marker+40, 2*inspectorRowHeight+1)
+	require.Same(t, v.MustGetByID("box"), i.selected)
+
+	// Clicking a view on the screen selects it.
+	i.click(v.MustGetByID("other").frame.Min.X+1, 1)
+	require.Same(t, v.MustGetByID("other"), i.selected)
+}
+
+func TestInspectorDetails(t *testing.T) {
+	i, v := setupInspector(t)
+	i.selected = v.MustGetByID("box")
+	rows := i.buildRows()
+	texts := rowTexts(rows)
+	require.Contains(t, texts, "view#box.panel.dark")
+	require.Contains(t, texts, "frame   (10, 0)-(60, 40) 50x40")
+	require.Contains(t, texts, "margin  0 0 0 10")
+	require.Contains(t, texts, "MarginLeft: 10")
+	require.Contains(t, texts, `class="panel dark"`)
+	require.Contains(t, texts, `data-x="1"`)
+
+	colorOf := func(text string) color.Color {
+		for _, r := range rows {
+			if r.text == text {
+				return r.color
+			}
+		}
+		return nil
+	}
+	require.Equal(t, inspectorDimColor, colorOf("width: 100  style"))
+	require.Equal(t, inspectorTextColor, colorOf("width: 50  @media (max-width: 600px)"))
+}
+
+func TestInspectorEdit(t *testing.T) {
+	i, v := setupInspector(t)
+	box := v.MustGetByID("box")
+	i.edit(1)
+	require.Equal(t, 0, box.Left)
+
+	i.selected = box
+	i.field = 2
+	require.Equal(t, "width", inspectorFields[i.field].name)
+	i.edit(10)
+	require.Equal(t, 60, box.Width)
+	require.True(t, box.isDirty)
+	v.Draw(image.NewRGBA(image.Rect(0, 0, 400, 300)))
+	require.Equal(t, 60, box.frame.Dx())
+
+	i.edit(-100)
+	require.Equal(t, 0, box.Width)
+
+	i.field = 4
+	i.edit(-20)
+	require.Equal(t, -10, box.MarginLeft)
+
+	i.field = 8
+	i.edit(5)
+	require.Equal(t, .5, box.Grow)
+	i.hovered = box
+	i.Draw(image.NewRGBA(image.Rect(0, 0, 800, 300)))
+}
+
+func TestInspectorTransformedView(t *testing.T) {
+	i, v := setupInspector(t)
+	other := v.MustGetByID("other")
+	other.SetTransform(&Transform{TranslateX: 100})
+	f := other.frame
+
+	i.hover(f.Min.X+1, f.Min.Y+1)
+	require.Same(t, v, i.hovered)
+	i.hover(f.Min.X+101, f.Min.Y+1)
+	require.Same(t, other, i.hovered)
+
+	r := &Recorder{}
+	i.render(r, image.Pt(800, 300))
+	ops := r.Ops()
+	require.Equal(t, DrawBeginLayer, ops[0])
+	require.Contains(t, ops, DrawEndLayer)
+	for _, c := range r.Commands {
+		if c.Op == DrawEndLayer {
+			x, y := c.Layer.GeoM.Apply(0, 0)
+			require.Equal(t, [2]float64{100, 0}, [2]float64{x, y})
+		}
+	}
+}
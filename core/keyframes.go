@@ -0,0 +1,582 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Keyframes are the frames of a keyframe animation, like @keyframes in CSS.
+type Keyframes struct {
+	Name   string
+	Frames []Keyframe
+}
+
+// Keyframe is the values of properties at an offset of a keyframe animation.
+type Keyframe struct {
+	// Offset is the offset of the frame from 0 to 1.
+	Offset float64
+	Values map[Property]float64
+}
+
+var registeredKeyframes = map[string]*Keyframes{}
+
+// RegisterKeyframes registers keyframes so that all views can play them by name.
+// The @keyframes of the stylesheets of a parsed document are not registered:
+// only the views of the document play them, instead of the registered ones with the same name.
+func RegisterKeyframes(k *Keyframes) {
+	registeredKeyframes[k.Name] = k
+}
+
+// findKeyframes returns the keyframes with the name from the nearest parsed document
+// of the view, or the registered ones.
+func (v *View) findKeyframes(name string) (*Keyframes, bool) {
+	for p := v; p != nil; p = p.parent {
+		if k, ok := p.keyframes[name]; ok {
+			return k, true
+		}
+	}
+	k, ok := registeredKeyframes[name]
+	return k, ok
+}
+
+// AnimationDirection is the direction in which the iterations of a keyframe animation are played.
+type AnimationDirection uint8
+
+const (
+	AnimationDirectionNormal AnimationDirection = iota
+	AnimationDirectionReverse
+	AnimationDirectionAlternate
+	AnimationDirectionAlternateReverse
+)
+
+// AnimationFillMode is whether a keyframe animation applies its values
+// before it starts and after it ends.
+type AnimationFillMode uint8
+
+const (
+	AnimationFillModeNone AnimationFillMode = iota
+	AnimationFillModeForwards
+	AnimationFillModeBackwards
+	AnimationFillModeBoth
+)
+
+// KeyframeAnimation plays registered keyframes on a view, like the animation property in CSS.
+type KeyframeAnimation struct {
+	// Name is the name of the keyframes.
+	Name     string
+	Duration time.Duration
+	Delay    time.Duration
+	// Easing is the easing between the keyframes. It is Linear if nil.
+	Easing Easing
+	// Iterations is the number of times the animation is played.
+	// It is played forever if it is negative. If it is zero, the animation
+	// finishes as soon as its delay has passed, at the start of the first iteration.
+	Iterations int
+	Direction  AnimationDirection
+	FillMode   AnimationFillMode
+}
+
+// AnimationEndHandler represents a component that is notified
+// when a keyframe animation of its view has finished.
+type AnimationEndHandler interface {
+	HandleAnimationEnd(v *View, name string)
+}
+
+// AnimationState is the state of a keyframe animation of a view.
+type AnimationState struct {
+	Name string
+	// Elapsed is the time the animation has played, including its delay.
+	Elapsed time.Duration
+	// Iteration is the index of the current iteration.
+	Iteration int
+	// Progress is the progress of the current iteration from 0 to 1.
+	Progress float64
+	Paused   bool
+	Finished bool
+}
+
+// keyPoint is the value of a property at an offset.
+type keyPoint struct {
+	offset, value float64
+}
+
+// keyframePlayer plays a keyframe animation on a view.
+type keyframePlayer struct {
+	spec     KeyframeAnimation
+	view     *View
+	tracks   map[Property][]keyPoint
+	base     map[Property]float64
+	elapsed  time.Duration
+	paused   bool
+	finished bool
+}
+
+func newKeyframePlayer(v *View, spec KeyframeAnimation, k *Keyframes) *keyframePlayer {
+	p := &keyframePlayer{
+		spec:   spec,
+		view:   v,
+		tracks: map[Property][]keyPoint{},
+		base:   map[Property]float64{},
+	}
+	frames := append([]Keyframe(nil), k.Frames...)
+	sort.SliceStable(frames, func(i, j int) bool { return frames[i].Offset < frames[j].Offset })
+	for _, f := range frames {
+		for prop, val := range f.Values {
+			if _, ok := p.base[prop]; !ok {
+				p.base[prop] = prop.get(v)
+			}
+			p.tracks[prop] = append(p.tracks[prop], keyPoint{f.Offset, val})
+		}
+	}
+	// The missing first and last frames are the values of the view.
+	for prop, t := range p.tracks {
+		if t[0].offset > 0 {
+			t = append([]keyPoint{{0, p.base[prop]}}, t...)
+		}
+		if t[len(t)-1].offset < 1 {
+			t = append(t, keyPoint{1, p.base[prop]})
+		}
+		p.tracks[prop] = t
+	}
+	return p
+}
+
+// isReversed returns true if the iteration is played backwards.
+func (p *keyframePlayer) isReversed(iteration int) bool {
+	switch p.spec.Direction {
+	case AnimationDirectionReverse:
+		return true
+	case AnimationDirectionAlternate:
+		return iteration%2 == 1
+	case AnimationDirectionAlternateReverse:
+		return iteration%2 == 0
+	}
+	return false
+}
+
+// apply sets the properties of the view to their values at the progress of the keyframes.
+func (p *keyframePlayer) apply(progress float64) {
+	ease := p.spec.Easing
+	if ease == nil {
+		ease = Linear
+	}
+	for prop, t := range p.tracks {
+		i := sort.Search(len(t), func(i int) bool { return t[i].offset >= progress })
+		switch {
+		case i == 0:
+			prop.set(p.view, t[0].value)
+		case i == len(t):
+			prop.set(p.view, t[len(t)-1].value)
+		default:
+			a, b := t[i-1], t[i]
+			local := 0.0
+			if b.offset > a.offset {
+				local = (progress - a.offset) / (b.offset - a.offset)
+			}
+			prop.set(p.view, a.value+(b.value-a.value)*ease(local))
+		}
+	}
+}
+
+// restore sets the properties back to the values of the view before the animation.
+func (p *keyframePlayer) restore() {
+	for prop, v := range p.base {
+		prop.set(p.view, v)
+	}
+}
+
+// state returns the iteration and its progress.
+// An animation without iterations stays at the start of the first one.
+func (p *keyframePlayer) state() (int, float64) {
+	active := p.elapsed - p.spec.Delay
+	if active < 0 || p.spec.Duration <= 0 || p.spec.Iterations == 0 {
+		return 0, 0
+	}
+	iteration := int(active / p.spec.Duration)
+	progress := float64(active%p.spec.Duration) / float64(p.spec.Duration)
+	if n := p.spec.Iterations; n >= 0 && iteration >= n {
+		return n - 1, 1
+	}
+	return iteration, progress
+}
+
+func (p *keyframePlayer) step(dt time.Duration) (time.Duration, bool) {
+	if p.finished {
+		return dt, true
+	}
+	if p.paused {
+		return 0, false
+	}
+	p.elapsed += dt
+	fill := p.spec.FillMode
+	active := p.elapsed - p.spec.Delay
+	if active < 0 {
+		if fill == AnimationFillModeBackwards || fill == AnimationFillModeBoth {
+			p.apply(p.progress(0, 0))
+		}
+		return 0, false
+	}
+	n := p.spec.Iterations
+	total := p.spec.Duration * time.Duration(n)
+	if n >= 0 && active >= total {
+		switch {
+		case p.fillsForwards() && n == 0:
+			// An animation without iterations ends at the start of the first one.
+			p.apply(p.progress(0, 0))
+		case p.fillsForwards():
+			p.apply(p.progress(n-1, 1))
+		default:
+			p.restore()
+		}
+		p.finished = true
+		if h, ok := p.view.Handler.(AnimationEndHandler); ok {
+			h.HandleAnimationEnd(p.view, p.spec.Name)
+		}
+		return active - total, true
+	}
+	if p.spec.Duration <= 0 {
+		// An endless animation without a duration stays at its first frame.
+		p.apply(p.progress(0, 0))
+		return 0, false
+	}
+	p.apply(p.progress(p.state()))
+	return 0, false
+}
+
+// fillsForwards returns true if the values of the last frame are kept after the animation.
+func (p *keyframePlayer) fillsForwards() bool {
+	return p.spec.FillMode == AnimationFillModeForwards || p.spec.FillMode == AnimationFillModeBoth
+}
+
+// progress returns the offset of the keyframes at the progress of the iteration.
+func (p *keyframePlayer) progress(iteration int, progress float64) float64 {
+	if p.isReversed(iteration) {
+		return 1 - progress
+	}
+	return progress
+}
+
+func (p *keyframePlayer) reset() {
+	p.elapsed = 0
+	p.finished = false
+}
+
+// syncKeyframeAnimations starts the keyframe animations of the view tree that
+// have been added since the last update and stops the ones that have been removed.
+func (v *View) syncKeyframeAnimations() {
+	if len(v.KeyframeAnimations) > 0 || len(v.keyframePlayers) > 0 {
+		players := v.keyframePlayers[:0]
+		for _, p := range v.keyframePlayers {
+			if v.hasKeyframeAnimation(p.spec.Name) {
+				players = append(players, p)
+				continue
+			}
+			if !p.finished || p.fillsForwards() {
+				p.restore()
+			}
+			p.finished = true
+		}
+		v.keyframePlayers = players
+		for _, spec := range v.KeyframeAnimations {
+			if v.keyframePlayer(spec.Name) != nil {
+				continue
+			}
+			k, ok := v.findKeyframes(spec.Name)
+			if !ok {
+				warnf("unknown keyframes: %s", spec.Name)
+				k = &Keyframes{Name: spec.Name}
+			}
+			p := newKeyframePlayer(v, spec, k)
+			v.keyframePlayers = append(v.keyframePlayers, p)
+			v.Animate(p)
+		}
+	}
+	for _, c := range v.children {
+		c.item.syncKeyframeAnimations()
+	}
+}
+
+func (v *View) hasKeyframeAnimation(name string) bool {
+	for _, spec := range v.KeyframeAnimations {
+		if spec.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *View) keyframePlayer(name string) *keyframePlayer {
+	for _, p := range v.keyframePlayers {
+		if p.spec.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// SetKeyframeAnimations sets the keyframe animations of the view.
+// The added animations start on the next update.
+func (v *View) SetKeyframeAnimations(anims ...KeyframeAnimation) {
+	v.KeyframeAnimations = anims
+}
+
+// PauseAnimation pauses the keyframe animation with the name.
+// It returns false if the view is not playing the animation.
+func (v *View) PauseAnimation(name string) bool {
+	p := v.keyframePlayer(name)
+	if p == nil {
+		return false
+	}
+	p.paused = true
+	return true
+}
+
+// ResumeAnimation resumes the paused keyframe animation with the name.
+// It returns false if the view is not playing the animation.
+func (v *View) ResumeAnimation(name string) bool {
+	p := v.keyframePlayer(name)
+	if p == nil {
+		return false
+	}
+	p.paused = false
+	return true
+}
+
+// RestartAnimation plays the keyframe animation with the name from the start,
+// even if it has finished. It returns false if the view is not playing the animation.
+func (v *View) RestartAnimation(name string) bool {
+	p := v.keyframePlayer(name)
+	if p == nil {
+		return false
+	}
+	if p.finished {
+		v.Animate(p)
+	}
+	p.reset()
+	return true
+}
+
+// AnimationState returns the state of the keyframe animation with the name.
+// It returns false if the view is not playing the animation.
+func (v *View) AnimationState(name string) (AnimationState, bool) {
+	p := v.keyframePlayer(name)
+	if p == nil {
+		return AnimationState{}, false
+	}
+	iteration, progress := p.state()
+	return AnimationState{
+		Name:      name,
+		Elapsed:   p.elapsed,
+		Iteration: iteration,
+		Progress:  progress,
+		Paused:    p.paused,
+		Finished:  p.finished,
+	}, true
+}
+
+// extractKeyframes removes the @keyframes blocks from the stylesheets of doc.
+// It returns the remaining document and the keyframes by name.
+func extractKeyframes(doc string) (string, map[string]*Keyframes) {
+	keyframes := map[string]*Keyframes{}
+	doc = replaceStyleSheets(doc, func(tag, css string) string {
+		return tag + splitKeyframes(css, keyframes) + "</style>"
+	})
+	return doc, keyframes
+}
+
+// splitKeyframes removes the @keyframes blocks from css and adds them to keyframes.
+func splitKeyframes(css string, keyframes map[string]*Keyframes) string {
+	sb := &strings.Builder{}
+	errs := &ErrorList{}
+	for {
+		i := strings.Index(css, "@keyframes")
+		if i < 0 {
+			sb.WriteString(css)
+			break
+		}
+		sb.WriteString(css[:i])
+		open := strings.Index(css[i:], "{")
+		if open < 0 {
+			sb.WriteString(css[i:])
+			break
+		}
+		open += i
+		close := matchingBrace(css, open)
+		if close < 0 {
+			sb.WriteString(css[i:])
+			break
+		}
+		name := strings.TrimSpace(css[i+len("@keyframes") : open])
+		k, err := parseKeyframes(name, css[open+1:close])
+		if err != nil {
+			errs.Add(err)
+		} else {
+			keyframes[k.Name] = k
+		}
+		css = css[close+1:]
+	}
+	if errs.HasErrors() {
+		warnf("parse keyframes errors: %v", errs)
+	}
+	return sb.String()
+}
+
+// parseKeyframes parses the body of a @keyframes block,
+// e.g. `from { opacity: 0 } 50% { opacity: 1 } to { opacity: 0.5 }`.
+func parseKeyframes(name, body string) (*Keyframes, error) {
+	if name == "" || strings.ContainsAny(name, " \t\n") {
+		return nil, fmt.Errorf("invalid keyframes name: %q", name)
+	}
+	k := &Keyframes{Name: name}
+	for {
+		open := strings.Index(body, "{")
+		if open < 0 {
+			if strings.TrimSpace(body) != "" {
+				return nil, fmt.Errorf("invalid keyframes %s: %s", name, body)
+			}
+			break
+		}
+		close := matchingBrace(body, open)
+		if close < 0 {
+			return nil, fmt.Errorf("invalid keyframes %s: %s", name, body)
+		}
+		values, err := parseKeyframeValues(body[open+1 : close])
+		if err != nil {
+			return nil, fmt.Errorf("invalid keyframes %s: %w", name, err)
+		}
+		for _, sel := range splitValues(body[:open], ',') {
+			offset, err := parseKeyframeSelector(sel)
+			if err != nil {
+				return nil, err
+			}
+			k.Frames = append(k.Frames, Keyframe{Offset: offset, Values: values})
+		}
+		body = body[close+1:]
+	}
+	return k, nil
+}
+
+func parseKeyframeSelector(sel string) (float64, error) {
+	switch sel {
+	case "from":
+		return 0, nil
+	case "to":
+		return 1, nil
+	}
+	pct, err := strconv.ParseFloat(strings.TrimSuffix(sel, "%"), 64)
+	if err != nil || !strings.HasSuffix(sel, "%") || pct < 0 || pct > 100 {
+		return 0, fmt.Errorf("invalid keyframe selector: %s", sel)
+	}
+	return pct / 100, nil
+}
+
+// parseKeyframeValues returns the values of the animatable properties of the declarations.
+func parseKeyframeValues(decls string) (map[Property]float64, error) {
+	values := map[Property]float64{}
+	for _, decl := range strings.Split(decls, ";") {
+		kv := strings.SplitN(decl, ":", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		name := strings.TrimSpace(kv[0])
+		props, ok := transitionProperties(name)
+		mapper, mapped := styleMapper[name]
+		if !ok || !mapped || name == "all" {
+			return nil, fmt.Errorf("property cannot be animated: %s", name)
+		}
+		parsed, err := mapper.parseFunc(strings.TrimSpace(kv[1]))
+		if err != nil {
+			return nil, err
+		}
+		// The declaration is applied to a blank view to read the values.
+		v := &View{}
+		mapper.setFunc(v, parsed)
+		for _, p := range props {
+			values[p] = p.get(v)
+		}
+	}
+	return values, nil
+}
+
+// parseAnimation parses the CSS animation property,
+// e.g. `pulse 1s ease-in-out 200ms infinite alternate both`.
+func parseAnimation(val string) (any, error) {
+	if val == "none" {
+		return []KeyframeAnimation(nil), nil
+	}
+	var anims []KeyframeAnimation
+	for _, part := range splitValues(val, ',') {
+		a := KeyframeAnimation{Iterations: 1}
+		times := 0
+		for _, f := range splitValues(part, ' ') {
+			if d, err := parseDuration(f); err == nil {
+				if times == 0 {
+					a.Duration = d
+				} else {
+					a.Delay = d
+				}
+				times++
+				continue
+			}
+			if e, err := parseEasing(f); err == nil {
+				a.Easing = e
+				continue
+			}
+			if d, ok := animationDirections[f]; ok {
+				a.Direction = d
+				continue
+			}
+			if m, ok := animationFillModes[f]; ok {
+				a.FillMode = m
+				continue
+			}
+			if f == "infinite" {
+				a.Iterations = -1
+				continue
+			}
+			if n, err := strconv.Atoi(f); err == nil && n >= 0 {
+				a.Iterations = n
+				continue
+			}
+			if a.Name != "" || !isIdent(f) {
+				return nil, fmt.Errorf("invalid animation: %s", part)
+			}
+			a.Name = f
+		}
+		if a.Name == "" || times > 2 {
+			return nil, fmt.Errorf("invalid animation: %s", part)
+		}
+		anims = append(anims, a)
+	}
+	return anims, nil
+}
+
+var animationDirections = map[string]AnimationDirection{
+	"normal":            AnimationDirectionNormal,
+	"reverse":           AnimationDirectionReverse,
+	"alternate":         AnimationDirectionAlternate,
+	"alternate-reverse": AnimationDirectionAlternateReverse,
+}
+
+var animationFillModes = map[string]AnimationFillMode{
+	"none":      AnimationFillModeNone,
+	"forwards":  AnimationFillModeForwards,
+	"backwards": AnimationFillModeBackwards,
+	"both":      AnimationFillModeBoth,
+}
+
+// isIdent returns true if s is a CSS identifier such as a keyframes name.
+func isIdent(s string) bool {
+	for i, r := range s {
+		switch {
+		case r == '-' || r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z':
+		case r >= '0' && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return s != ""
+}
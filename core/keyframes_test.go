@@ -0,0 +1,205 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type animationEndHandler struct {
+	ended []string
+}
+
+func (h *animationEndHandler) HandleAnimationEnd(v *View, name string) {
+	h.ended = append(h.ended, name)
+}
+
+func TestParseKeyframes(t *testing.T) {
+	resetComponents()
+	v := Parse(`
+		<head><style>
+			@keyframes pulse {
+				from, to { transform: scale(1); }
+				50% { transform: scale(1.2); opacity: 0.5; }
+			}
+			.box { width: 10px; height: 10px; }
+		</style></head>
+		<body>
+			<view style="width: 100; height: 100">
+				<view id="a" class="box" style="animation: pulse 2s ease-in-out 100ms infinite alternate both"></view>
+			</view>
+		</body>`, nil)
+
+	require.NotContains(t, registeredKeyframes, "pulse", "the keyframes belong to the document")
+	k := v.keyframes["pulse"]
+	require.NotNil(t, k)
+	require.Len(t, k.Frames, 3)
+	require.Equal(t, 0., k.Frames[0].Offset)
+	require.Equal(t, 1., k.Frames[1].Offset)
+	require.Equal(t, 0.5, k.Frames[2].Offset)
+	require.Equal(t, 1.2, k.Frames[2].Values[PropertyScaleX])
+	require.Equal(t, 0.5, k.Frames[2].Values[PropertyOpacity])
+	require.Equal(t, 0., k.Frames[2].Values[PropertyRotate])
+
+	a := v.MustGetByID("a")
+	require.Equal(t, 10, a.Width, "the other rules still apply")
+	require.Len(t, a.KeyframeAnimations, 1)
+	anim := a.KeyframeAnimations[0]
+	require.Equal(t, "pulse", anim.Name)
+	require.Equal(t, 2*time.Second, anim.Duration)
+	require.Equal(t, 100*time.Millisecond, anim.Delay)
+	require.Equal(t, -1, anim.Iterations)
+	require.Equal(t, AnimationDirectionAlternate, anim.Direction)
+	require.Equal(t, AnimationFillModeBoth, anim.FillMode)
+
+	for _, val := range []string{"1s", "a b 1s", "spin 1s 1s 1s", "spin -1"} {
+		_, err := parseAnimation(val)
+		require.Error(t, err, val)
+	}
+	for _, body := range []string{"50 { opacity: 0 }", "from { color: red }", "from { opacity: 0 "} {
+		_, err := parseKeyframes("k", body)
+		require.Error(t, err, body)
+	}
+}
+
+func TestKeyframeAnimation(t *testing.T) {
+	advance := fakeClock(t)
+	RegisterKeyframes(&Keyframes{Name: "grow", Frames: []Keyframe{
+		{Offset: 0.5, Values: map[Property]float64{PropertyWidth: 100}},
+		{Offset: 1, Values: map[Property]float64{PropertyWidth: 50}},
+	}})
+	h := &animationEndHandler{}
+	root := &View{Width: 200, Height: 100}
+	box := &View{Width: 20, Height: 10, Handler: h}
+	root.AddChild(box)
+	box.SetKeyframeAnimations(KeyframeAnimation{
+		Name: "grow", Duration: time.Second, Iterations: 2, Direction: AnimationDirectionAlternate,
+	})
+	root.Update()
+
+	advance(250 * time.Millisecond)
+	root.Update()
+	require.Equal(t, 60, box.frame.Dx(), "the missing first frame is the value of the view")
+	advance(1500 * time.Millisecond)
+	root.Update()
+	require.Equal(t, 60, box.Width, "the second iteration is reversed")
+
+	s, ok := box.AnimationState("grow")
+	require.True(t, ok)
+	require.Equal(t, 1, s.Iteration)
+	require.InDelta(t, 0.75, s.Progress, 1e-9)
+
+	require.True(t, box.PauseAnimation("grow"))
+	advance(time.Second)
+	root.Update()
+	require.Equal(t, 60, box.Width)
+	require.True(t, box.ResumeAnimation("grow"))
+
+	advance(500 * time.Millisecond)
+	root.Update()
+	require.Equal(t, 20, box.Width, "the values are restored without a fill mode")
+	require.Equal(t, []string{"grow"}, h.ended)
+	s, _ = box.AnimationState("grow")
+	require.True(t, s.Finished)
+
+	require.True(t, box.RestartAnimation("grow"))
+	advance(500 * time.Millisecond)
+	root.Update()
+	require.Equal(t, 100, box.Width)
+
+	box.SetKeyframeAnimations()
+	root.Update()
+	require.Equal(t, 20, box.Width, "the values are restored when the animation is removed")
+	_, ok = box.AnimationState("grow")
+	require.False(t, ok)
+}
+
+func TestKeyframeFillMode(t *testing.T) {
+	advance := fakeClock(t)
+	RegisterKeyframes(&Keyframes{Name: "fade", Frames: []Keyframe{
+		{Offset: 0, Values: map[Property]float64{PropertyOpacity: 0}},
+		{Offset: 1, Values: map[Property]float64{PropertyOpacity: 0.5}},
+	}})
+	root := &View{Width: 100, Height: 100}
+	root.SetKeyframeAnimations(KeyframeAnimation{
+		Name: "fade", Duration: time.Second, Delay: time.Second, Iterations: 1, FillMode: AnimationFillModeBoth,
+	})
+	root.Update()
+	require.Equal(t, 0., root.opacity(), "the first frame fills backwards during the delay")
+	advance(3 * time.Second)
+	root.Update()
+	require.Equal(t, 0.5, root.opacity())
+	require.False(t, root.IsAnimating())
+}
+
+func TestKeyframesScope(t *testing.T) {
+	fakeClock(t)
+	resetComponents()
+	RegisterKeyframes(&Keyframes{Name: "scoped", Frames: []Keyframe{
+		{Offset: 0, Values: map[Property]float64{PropertyWidth: 1}},
+	}})
+	parse := func(width int) *View {
+		return Parse(fmt.Sprintf(`
+			<head><style>
+				@keyframes scoped { from { width: %dpx; } to { width: %dpx; } }
+			</style></head>
+			<body>
+				<view style="width: 100; height: 100">
+					<view id="a" style="height: 10; animation: scoped 1s both"></view>
+				</view>
+			</body>`, width, width), nil)
+	}
+	first, second := parse(10), parse(20)
+	first.Update()
+	second.Update()
+	require.Equal(t, 10, first.MustGetByID("a").Width)
+	require.Equal(t, 20, second.MustGetByID("a").Width)
+
+	// The views of another tree play the registered keyframes.
+	root := &View{Width: 100, Height: 100}
+	box := &View{Height: 10}
+	root.AddChild(box)
+	box.SetKeyframeAnimations(KeyframeAnimation{Name: "scoped", Duration: time.Second, Iterations: 1})
+	root.Update()
+	require.Equal(t, 1, box.Width)
+
+	// They play the keyframes of a parsed document once added to it.
+	box.SetKeyframeAnimations()
+	root.Update()
+	root.RemoveChild(box)
+	first.AddChild(box)
+	box.SetKeyframeAnimations(KeyframeAnimation{Name: "scoped", Duration: time.Second, Iterations: 1})
+	first.Update()
+	require.Equal(t, 10, box.Width)
+}
+
+func TestKeyframeZeroIterations(t *testing.T) {
+	advance := fakeClock(t)
+	RegisterKeyframes(&Keyframes{Name: "once", Frames: []Keyframe{
+		{Offset: 0, Values: map[Property]float64{PropertyOpacity: 0.2}},
+		{Offset: 1, Values: map[Property]float64{PropertyOpacity: 0.8}},
+	}})
+	h := &animationEndHandler{}
+	root := &View{Width: 100, Height: 100, Handler: h}
+	root.SetKeyframeAnimations(KeyframeAnimation{
+		Name: "once", Duration: time.Second, Delay: time.Second, Iterations: 0, FillMode: AnimationFillModeForwards,
+	})
+	root.Update()
+	s, ok := root.AnimationState("once")
+	require.True(t, ok)
+	require.Equal(t, 0, s.Iteration)
+	require.False(t, s.Finished)
+	require.Equal(t, 1., root.opacity())
+
+	advance(time.Second)
+	root.Update()
+	s, _ = root.AnimationState("once")
+	require.Equal(t, 0, s.Iteration)
+	require.Equal(t, 0., s.Progress)
+	require.True(t, s.Finished)
+	require.Equal(t, 0.2, root.opacity(), "the start of the first iteration fills forwards")
+	require.Equal(t, []string{"once"}, h.ended)
+	require.False(t, root.IsAnimating())
+}
@@ -0,0 +1,60 @@
+package core
+
+import "image"
+
+// LayoutEngine lays out the children of a view.
+// Set it to View.LayoutEngine to use a custom layout such as an arc or a radial menu.
+type LayoutEngine interface {
+	// Layout sets the frame of each item inside the container.
+	// The width and height are the size of the container.
+	// Hidden (display: none) and absolutely positioned children are not passed to the engine.
+	Layout(container *View, width, height int, items []*LayoutItem)
+}
+
+// LayoutItem is a child view to be laid out by a LayoutEngine.
+type LayoutItem struct {
+	// View is the child view. Its properties such as Width, Height,
+	// the margins and the flex factors are the constraints of the layout.
+	View  *View
+	child *child
+}
+
+// Size returns the preferred size of the item.
+// Percentages are resolved against the given container size.
+func (it *LayoutItem) Size(containerWidth, containerHeight int) (int, int) {
+	w, h := it.View.width(), it.View.height()
+	if it.View.WidthInPct != 0 {
+		w = int(float64(containerWidth) * it.View.WidthInPct / 100)
+	}
+	if it.View.HeightInPct != 0 {
+		h = int(float64(containerHeight) * it.View.HeightInPct / 100)
+	}
+	return w, h
+}
+
+// SetFrame sets the frame of the item relative to the top-left corner of the container.
+func (it *LayoutItem) SetFrame(frame image.Rectangle) {
+	it.child.setBounds(frame, it.child.item.parent.frame.Min)
+}
+
+// FlexLayout is the LayoutEngine that implements the flexbox layout.
+// It is the default engine of a view.
+type FlexLayout struct{}
+
+var _ LayoutEngine = FlexLayout{}
+
+// Layout implements LayoutEngine.
+func (FlexLayout) Layout(container *View, width, height int, items []*LayoutItem) {
+	container.flexEmbed.View = container
+	container.layout(width, height, items)
+}
+
+func (v *View) layoutEngine() LayoutEngine {
+	if v.LayoutEngine != nil {
+		return v.LayoutEngine
+	}
+	if v.Display == DisplayAnchor {
+		return AnchorLayout{}
+	}
+	return FlexLayout{}
+}
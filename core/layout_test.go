@@ -0,0 +1,50 @@
+package core
+
+import (
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// diagonalLayout places the items along the diagonal of the container.
+type diagonalLayout struct {
+	container *View
+	width     int
+	height    int
+}
+
+func (d *diagonalLayout) Layout(container *View, width, height int, items []*LayoutItem) {
+	d.container, d.width, d.height = container, width, height
+	x, y := 0, 0
+	for _, it := range items {
+		w, h := it.Size(width, height)
+		it.SetFrame(image.Rect(x, y, x+w, y+h))
+		x, y = x+w, y+h
+	}
+}
+
+func TestCustomLayoutEngine(t *testing.T) {
+	engine := &diagonalLayout{}
+	root := &View{Left: 10, Top: 20, Width: 200, Height: 100, LayoutEngine: engine}
+	mocks := [3]mockHandler{}
+	root.AddChild(
+		&View{Width: 10, Height: 20, Handler: &mocks[0]},
+		&View{WidthInPct: 10, HeightInPct: 10, Handler: &mocks[1]},
+		&View{Width: 10, Height: 10, Display: DisplayNone},
+		&View{Width: 10, Height: 10, Position: PositionAbsolute, Left: 50, Handler: &mocks[2]},
+	)
+	root.Draw(nil)
+
+	require.Same(t, root, engine.container)
+	require.Equal(t, 200, engine.width)
+	require.Equal(t, 100, engine.height)
+	require.Equal(t, image.Rect(10, 20, 20, 40), mocks[0].Frame)
+	require.Equal(t, image.Rect(20, 40, 40, 50), mocks[1].Frame)
+	require.Equal(t, image.Rect(60, 20, 70, 30), mocks[2].Frame)
+
+	root.SetLayoutEngine(FlexLayout{})
+	root.Draw(nil)
+	require.Equal(t, image.Rect(10, 20, 20, 40), mocks[0].Frame)
+	require.Equal(t, image.Rect(20, 20, 40, 30), mocks[1].Frame)
+}
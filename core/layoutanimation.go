@@ -0,0 +1,214 @@
+package core
+
+import (
+	"image"
+	"math"
+	"time"
+)
+
+// LayoutAnimation animates the children of a view when its layout changes.
+// The children whose frames change move and resize from their old frames to the new ones.
+type LayoutAnimation struct {
+	Duration time.Duration
+	// Easing is the easing of the moves. It is Linear if nil.
+	Easing Easing
+	// Enter returns the animation of a child that is added, e.g. FadeIn. It can be nil.
+	Enter func(v *View) Animation
+	// Exit returns the animation of a child that is removed, e.g. FadeOut. It can be nil.
+	// The child is drawn until the animation has finished.
+	Exit func(v *View) Animation
+}
+
+// FadeIn returns an enter animation that fades a view in.
+func FadeIn(d time.Duration) func(v *View) Animation {
+	return func(v *View) Animation {
+		to := v.opacity()
+		v.SetOpacity(0)
+		return v.Tween(PropertyOpacity, to, d)
+	}
+}
+
+// FadeOut returns an exit animation that fades a view out.
+func FadeOut(d time.Duration) func(v *View) Animation {
+	return func(v *View) Animation {
+		return v.Tween(PropertyOpacity, 0, d)
+	}
+}
+
+// SetAnimateLayout sets the layout animation of the children of the view.
+// Passing nil disables it.
+func (v *View) SetAnimateLayout(a *LayoutAnimation) {
+	v.AnimateLayout = a
+	if a == nil {
+		v.layoutFrames = nil
+	}
+}
+
+// layoutFlip draws a view in a frame moving from the frame before a layout
+// to the frame after it.
+type layoutFlip struct {
+	from image.Rectangle
+	// t is the eased progress of the move from 0 to 1.
+	t     float64
+	tween *Tween
+}
+
+// rect returns the frame that the view is drawn in for its laid out frame.
+func (f *layoutFlip) rect(frame image.Rectangle) (x, y, w, h float64) {
+	lerp := func(a, b int) float64 { return float64(a) + float64(b-a)*f.t }
+	return lerp(f.from.Min.X, frame.Min.X), lerp(f.from.Min.Y, frame.Min.Y),
+		lerp(f.from.Dx(), frame.Dx()), lerp(f.from.Dy(), frame.Dy())
+}
+
+// geoM returns the matrix that maps the laid out frame to the drawn frame in pixels of the scale.
+func (f *layoutFlip) geoM(frame image.Rectangle, scale float64) GeoM {
+	x, y, w, h := f.rect(frame)
+	sx, sy := 1.0, 1.0
+	if frame.Dx() > 0 {
+		sx = w / float64(frame.Dx())
+	}
+	if frame.Dy() > 0 {
+		sy = h / float64(frame.Dy())
+	}
+	g := GeoM{}
+	g.Translate(-float64(frame.Min.X)*scale, -float64(frame.Min.Y)*scale)
+	g.Scale(sx, sy)
+	g.Translate(x*scale, y*scale)
+	return g
+}
+
+// visualFrame returns the frame that the view is drawn in, ignoring its transform.
+func (v *View) visualFrame() image.Rectangle {
+	if v.layoutFlip == nil {
+		return v.frame
+	}
+	x, y, w, h := v.layoutFlip.rect(v.frame)
+	return image.Rect(int(math.Round(x)), int(math.Round(y)), int(math.Round(x+w)), int(math.Round(y+h)))
+}
+
+// beginLayoutAnimation returns the frames the children are drawn in before a layout,
+// and whether the view animates its layout.
+func (v *View) beginLayoutAnimation() (map[*View]image.Rectangle, bool) {
+	if v.AnimateLayout == nil {
+		return nil, false
+	}
+	frames := make(map[*View]image.Rectangle, len(v.children))
+	for _, c := range v.children {
+		frames[c.item] = c.item.visualFrame()
+	}
+	return frames, true
+}
+
+// endLayoutAnimation animates the children whose frames have changed by the layout
+// and the children that have been added since the previous layout.
+func (v *View) endLayoutAnimation(before map[*View]image.Rectangle) {
+	a := v.AnimateLayout
+	previous := v.layoutFrames
+	v.layoutFrames = make(map[*View]image.Rectangle, len(v.children))
+	for _, c := range v.children {
+		cv := c.item
+		v.layoutFrames[cv] = cv.frame
+		if previous == nil {
+			continue
+		}
+		if _, ok := previous[cv]; !ok {
+			if a.Enter != nil {
+				if anim := a.Enter(cv); anim != nil {
+					cv.Animate(anim)
+				}
+			}
+			continue
+		}
+		if from := before[cv]; from != cv.frame {
+			cv.startLayoutFlip(from, a)
+		}
+	}
+}
+
+// startLayoutFlip moves the view from the frame to its laid out frame.
+func (v *View) startLayoutFlip(from image.Rectangle, a *LayoutAnimation) {
+	if v.layoutFlip != nil {
+		v.layoutFlip.tween.stopped = true
+	}
+	f := &layoutFlip{from: from}
+	f.tween = &Tween{
+		Set: func(t float64) {
+			f.t = t
+			v.invalidateParent()
+		},
+		From:     Float(0),
+		To:       1,
+		Duration: a.Duration,
+		Easing:   a.Easing,
+		OnComplete: func() {
+			if v.layoutFlip == f {
+				v.layoutFlip = nil
+			}
+		},
+	}
+	v.layoutFlip = f
+	v.invalidateParent()
+	v.Animate(f.tween)
+}
+
+// detachChild removes the parent of a removed child. If the view animates its layout,
+// the child is drawn until its exit animation has finished.
+func (v *View) detachChild(c *child) {
+	cv := c.item
+	if a := v.AnimateLayout; a != nil && a.Exit != nil && v.layoutFrames != nil {
+		opacity := cv.Opacity
+		if anim := a.Exit(cv); anim != nil {
+			c.exit = &Group{
+				Animations: []Animation{anim},
+				OnComplete: func() { v.removeExiting(c) },
+			}
+			c.exitOpacity = opacity
+			v.exiting = append(v.exiting, c)
+			v.Animate(c.exit)
+			return
+		}
+	}
+	cv.hasParent = false
+	cv.parent = nil
+}
+
+// cancelExit stops the exit animation of a child that is added again
+// and restores the opacity it had before the animation.
+func (c *child) cancelExit() {
+	c.exit.OnComplete = nil
+	stopTweens(c.exit)
+	c.item.Opacity = c.exitOpacity
+	c.item.invalidateParent()
+}
+
+// stopTweens stops the tweens of the animation and of its groups.
+func stopTweens(a Animation) {
+	switch a := a.(type) {
+	case *Tween:
+		a.stopped = true
+	case *Group:
+		for _, anim := range a.Animations {
+			stopTweens(anim)
+		}
+	}
+}
+
+// removeExiting stops drawing a removed child.
+func (v *View) removeExiting(c *child) {
+	for i, e := range v.exiting {
+		if e == c {
+			v.exiting = append(v.exiting[:i], v.exiting[i+1:]...)
+			v.Invalidate()
+			break
+		}
+	}
+	for _, child := range v.children {
+		if child.item == c.item {
+			return
+		}
+	}
+	if c.item.parent == v {
+		c.item.hasParent = false
+		c.item.parent = nil
+	}
+}
@@ -0,0 +1,114 @@
+package core
+
+import (
+	"image"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnimateLayout(t *testing.T) {
+	advance := fakeClock(t)
+	root := &View{Width: 300, Height: 100}
+	root.SetAnimateLayout(&LayoutAnimation{
+		Duration: time.Second,
+		Enter:    FadeIn(time.Second),
+		Exit:     FadeOut(time.Second),
+	})
+	a := &View{Width: 50, Height: 10}
+	b := &View{Width: 50, Height: 10}
+	root.AddChild(a, b)
+	root.Update()
+	require.Nil(t, b.layoutFlip, "the first layout is not animated")
+	require.Equal(t, 1., a.opacity())
+
+	require.True(t, root.RemoveChild(a))
+	root.Update()
+	require.Equal(t, image.Rect(0, 0, 50, 10), b.frame)
+	require.Equal(t, image.Rect(50, 0, 100, 10), b.visualFrame(), "the move starts at the old frame")
+	require.Len(t, root.exiting, 1)
+	require.Equal(t, root, a.parent, "the exiting view keeps its parent")
+	require.True(t, root.IsAnimating(), "the exit is tracked on the parent")
+	require.False(t, a.IsAnimating())
+	require.True(t, b.IsAnimating(), "the move is tracked on the moved view")
+
+	advance(500 * time.Millisecond)
+	root.Update()
+	require.Equal(t, image.Rect(25, 0, 75, 10), b.visualFrame())
+	require.Equal(t, 0.5, a.opacity())
+	x, y := b.toLocal(30, 5)
+	require.Equal(t, [2]int{5, 5}, [2]int{x, y}, "pointer events follow the move")
+
+	advance(600 * time.Millisecond)
+	root.Update()
+	require.Nil(t, b.layoutFlip)
+	require.Empty(t, root.exiting)
+	require.False(t, root.IsAnimating())
+	require.False(t, b.IsAnimating())
+	require.False(t, a.hasParent)
+	require.Nil(t, a.parent)
+
+	c := &View{Width: 50, Height: 10}
+	root.AddChild(c)
+	root.Update()
+	require.Equal(t, 0., c.opacity(), "the added view enters")
+	require.Nil(t, b.layoutFlip)
+	advance(500 * time.Millisecond)
+	root.Update()
+	require.Equal(t, 0.5, c.opacity())
+
+	// A resized sibling moves the following views.
+	b.SetWidth(100)
+	root.Update()
+	advance(500 * time.Millisecond)
+	root.Update()
+	require.Equal(t, image.Rect(75, 0, 125, 10), c.visualFrame())
+	c.StopAnimations()
+	require.Equal(t, c.frame, c.visualFrame(), "stopping the animations ends the move")
+}
+
+func TestAnimateLayoutWithoutExit(t *testing.T) {
+	root := &View{Width: 300, Height: 100}
+	root.SetAnimateLayout(&LayoutAnimation{Duration: time.Second})
+	a := &View{Width: 50, Height: 10}
+	root.AddChild(a)
+	root.Update()
+	require.Equal(t, a, root.PopChild())
+	require.Empty(t, root.exiting)
+	require.False(t, a.hasParent)
+}
+
+func TestAnimateLayoutReAdd(t *testing.T) {
+	advance := fakeClock(t)
+	root := &View{Width: 300, Height: 100}
+	root.SetAnimateLayout(&LayoutAnimation{
+		Duration: time.Second,
+		Enter:    FadeIn(time.Second),
+		Exit:     FadeOut(time.Second),
+	})
+	a := &View{Width: 50, Height: 10}
+	root.AddChild(a)
+	root.Update()
+
+	root.RemoveChild(a)
+	root.Update()
+	advance(500 * time.Millisecond)
+	root.Update()
+	require.Equal(t, 0.5, a.opacity())
+
+	// The view added during its exit fades in to its opacity before the exit.
+	root.AddChild(a)
+	require.Empty(t, root.exiting)
+	require.Equal(t, 1., a.opacity())
+	root.Update()
+	require.Equal(t, 0., a.opacity())
+	advance(500 * time.Millisecond)
+	root.Update()
+	require.Equal(t, 0.5, a.opacity())
+	advance(600 * time.Millisecond)
+	root.Update()
+	require.Equal(t, 1., a.opacity())
+	require.True(t, a.hasParent)
+	require.Equal(t, root, a.parent)
+}
@@ -0,0 +1,419 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// mediaQueryList is a comma separated list of media queries.
+// It matches when any of the queries matches.
+type mediaQueryList []mediaQuery
+
+// mediaQuery is a single media query such as `screen and (max-width: 600px)`.
+// It matches when all of its features match.
+type mediaQuery struct {
+	not      bool
+	never    bool
+	features []mediaFeature
+}
+
+type mediaFeature struct {
+	name string
+	num  float64
+	str  string
+}
+
+func (l mediaQueryList) matches(width, height int) bool {
+	for _, q := range l {
+		if q.matches(width, height) {
+			return true
+		}
+	}
+	return false
+}
+
+func (q mediaQuery) matches(width, height int) bool {
+	ok := !q.never
+	if ok {
+		for _, f := range q.features {
+			if !f.matches(width, height) {
+				ok = false
+				break
+			}
+		}
+	}
+	if q.not {
+		return !ok
+	}
+	return ok
+}
+
+func (f mediaFeature) matches(width, height int) bool {
+	w, h := float64(width), float64(height)
+	switch f.name {
+	case "width":
+		return w == f.num
+	case "min-width":
+		return w >= f.num
+	case "max-width":
+		return w <= f.num
+	case "height":
+		return h == f.num
+	case "min-height":
+		return h >= f.num
+	case "max-height":
+		return h <= f.num
+	case "orientation":
+		if f.str == "portrait" {
+			return h >= w
+		}
+		return w > h
+	case "aspect-ratio", "min-aspect-ratio", "max-aspect-ratio":
+		if h == 0 {
+			return false
+		}
+		r := w / h
+		switch f.name {
+		case "min-aspect-ratio":
+			return r >= f.num
+		case "max-aspect-ratio":
+			return r <= f.num
+		}
+		return r == f.num
+	}
+	return false
+}
+
+func parseMediaQueryList(s string) (mediaQueryList, error) {
+	var list mediaQueryList
+	for _, part := range strings.Split(s, ",") {
+		q, err := parseMediaQuery(strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, q)
+	}
+	return list, nil
+}
+
+func parseMediaQuery(s string) (mediaQuery, error) {
+	q := mediaQuery{}
+	s = strings.ToLower(s)
+	for i, term := range splitMediaTerms(s) {
+		switch {
+		case i == 0 && term == "not":
+			q.not = true
+		case term == "only", term == "and", term == "all", term == "screen":
+		case term == "print", term == "speech":
+			q.never = true
+		case strings.HasPrefix(term, "(") && strings.HasSuffix(term, ")"):
+			f, err := parseMediaFeature(term[1 : len(term)-1])
+			if err != nil {
+				return q, err
+			}
+			q.features = append(q.features, f)
+		default:
+			return q, fmt.Errorf("unknown media query: %s", term)
+		}
+	}
+	return q, nil
+}
+
+// splitMediaTerms splits a media query into keywords and parenthesized features.
+func splitMediaTerms(s string) []string {
+	var terms []string
+	depth, start := 0, -1
+	for i, r := range s {
+		switch {
+		case r == '(':
+			if depth == 0 {
+				start = i
+			}
+			depth++
+		case r == ')':
+			depth--
+			if depth == 0 && start >= 0 {
+				terms = append(terms, s[start:i+1])
+				start = -1
+			}
+		case depth == 0 && unicode.IsSpace(r):
+			if start >= 0 {
+				terms = append(terms, s[start:i])
+				start = -1
+			}
+		case depth == 0 && start < 0:
+			start = i
+		}
+	}
+	if start >= 0 {
+		terms = append(terms, s[start:])
+	}
+	return terms
+}
+
+func parseMediaFeature(s string) (mediaFeature, error) {
+	kv := strings.SplitN(s, ":", 2)
+	if len(kv) != 2 {
+		return mediaFeature{}, fmt.Errorf("invalid media feature: %s", s)
+	}
+	f := mediaFeature{name: strings.TrimSpace(kv[0])}
+	val := strings.TrimSpace(kv[1])
+	switch f.name {
+	case "width", "min-width", "max-width", "height", "min-height", "max-height":
+		n, err := strconv.ParseFloat(strings.TrimSuffix(val, "px"), 64)
+		if err != nil {
+			return f, fmt.Errorf("invalid media feature value: %s", s)
+		}
+		f.num = n
+	case "orientation":
+		if val != "portrait" && val != "landscape" {
+			return f, fmt.Errorf("unknown orientation: %s", val)
+		}
+		f.str = val
+	case "aspect-ratio", "min-aspect-ratio", "max-aspect-ratio":
+		r, err := parseRatio(val)
+		if err != nil {
+			return f, err
+		}
+		f.num = r
+	default:
+		return f, fmt.Errorf("unknown media feature: %s", f.name)
+	}
+	return f, nil
+}
+
+func parseRatio(val string) (float64, error) {
+	nd := strings.SplitN(val, "/", 2)
+	n, err := strconv.ParseFloat(strings.TrimSpace(nd[0]), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid ratio: %s", val)
+	}
+	if len(nd) == 1 {
+		return n, nil
+	}
+	d, err := strconv.ParseFloat(strings.TrimSpace(nd[1]), 64)
+	if err != nil || d == 0 {
+		return 0, fmt.Errorf("invalid ratio: %s", val)
+	}
+	return n / d, nil
+}
+
+// mediaBlock is the content of an @media block in a stylesheet.
+type mediaBlock struct {
+	query mediaQueryList
+	// text is the source of the query, e.g. `(max-width: 600px)`.
+	text string
+	css  string
+}
+
+// mediaMarker marks the place of a removed @media block in a document.
+const mediaMarker = "\x00media\x00"
+
+// mediaSheet computes the styles of the views of a parsed document with @media blocks.
+// The stylesheets are inlined again with the matching blocks in their place,
+// so that their rules cascade with the others by specificity and source order.
+type mediaSheet struct {
+	// parts are the document split at the places of the blocks.
+	parts  []string
+	blocks []mediaBlock
+	cms    cms
+	views  int
+	// styles are the styles of the views for each key of matching blocks.
+	styles map[string][]string
+}
+
+// extractMediaBlocks removes the @media blocks from the stylesheets of doc.
+// It returns the remaining document and the sheet of the removed blocks.
+func extractMediaBlocks(doc string) (string, *mediaSheet) {
+	s := &mediaSheet{styles: map[string][]string{}}
+	doc = replaceStyleSheets(doc, func(tag, css string) string {
+		rest, bs := splitMediaBlocks(css)
+		s.blocks = append(s.blocks, bs...)
+		return tag + rest + "</style>"
+	})
+	s.parts = strings.Split(doc, mediaMarker)
+	return strings.Join(s.parts, ""), s
+}
+
+// splitMediaBlocks replaces the @media blocks of css with mediaMarker.
+// The blocks with an invalid query are removed.
+func splitMediaBlocks(css string) (string, []mediaBlock) {
+	var blocks []mediaBlock
+	sb := &strings.Builder{}
+	for {
+		i := strings.Index(css, "@media")
+		if i < 0 {
+			sb.WriteString(css)
+			break
+		}
+		sb.WriteString(css[:i])
+		open := strings.Index(css[i:], "{")
+		if open < 0 {
+			sb.WriteString(css[i:])
+			break
+		}
+		open += i
+		close := matchingBrace(css, open)
+		if close < 0 {
+			sb.WriteString(css[i:])
+			break
+		}
+		prelude := strings.TrimSpace(css[i+len("@media") : open])
+		query, err := parseMediaQueryList(prelude)
+		if err != nil {
+			warnf("parse media query errors: %v", err)
+		} else {
+			blocks = append(blocks, mediaBlock{query: query, text: prelude, css: css[open+1 : close]})
+			sb.WriteString(mediaMarker)
+		}
+		css = css[close+1:]
+	}
+	return sb.String(), blocks
+}
+
+func matchingBrace(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// replaceStyleSheets replaces every <style> element of doc with
+// the result of fn called with the opening tag and the content of the element.
+func replaceStyleSheets(doc string, fn func(tag, css string) string) string {
+	sb := &strings.Builder{}
+	lower := strings.ToLower(doc)
+	pos := 0
+	for {
+		start := strings.Index(lower[pos:], "<style")
+		if start < 0 {
+			break
+		}
+		start += pos
+		open := strings.Index(lower[start:], ">")
+		if open < 0 {
+			break
+		}
+		open += start + 1
+		end := strings.Index(lower[open:], "</style>")
+		if end < 0 {
+			break
+		}
+		end += open
+		sb.WriteString(doc[pos:start])
+		sb.WriteString(fn(doc[start:open], doc[open:end]))
+		pos = end + len("</style>")
+	}
+	sb.WriteString(doc[pos:])
+	return sb.String()
+}
+
+// setMediaSheet attaches the sheet to the views that were created from its document.
+func setMediaSheet(s *mediaSheet, views []*View, cms cms) {
+	s.cms, s.views = cms, len(views)
+	base := make([]string, len(views))
+	for i, v := range views {
+		base[i] = v.css.base
+		v.css.sheet, v.css.index = s, i
+	}
+	s.styles[strings.Repeat("0", len(s.blocks))] = base
+}
+
+// key returns the key of the blocks that match the size: '1' for a matching block, '0' otherwise.
+func (s *mediaSheet) key(width, height int) string {
+	key := make([]byte, len(s.blocks))
+	for i, b := range s.blocks {
+		key[i] = '0'
+		if b.query.matches(width, height) {
+			key[i] = '1'
+		}
+	}
+	return string(key)
+}
+
+// stylesOf returns the style of each view when the blocks of the key match.
+// It returns nil if the inlined document doesn't have an element for each view.
+func (s *mediaSheet) stylesOf(key string) []string {
+	if styles, ok := s.styles[key]; ok {
+		return styles
+	}
+	sb := &strings.Builder{}
+	for i, part := range s.parts {
+		if i > 0 && key[i-1] == '1' {
+			sb.WriteString(s.blocks[i-1].css)
+		}
+		sb.WriteString(part)
+	}
+	styles := collectStyles(inlineCSS(sb.String()), s.cms)
+	if len(styles) != s.views {
+		warnf("media queries skipped: the document has %d elements for %d views", len(styles), s.views)
+		styles = nil
+	}
+	s.styles[key] = styles
+	return styles
+}
+
+// applyMediaQueries applies to the view tree the styles of the media blocks that match the given size.
+// Only the properties whose CSS values change are set, so that the values set at runtime
+// to the other properties are kept. The size of the root view is kept as is.
+func (v *View) applyMediaQueries(width, height int) {
+	if c := v.css; c != nil && c.sheet != nil {
+		if styles := c.sheet.stylesOf(c.sheet.key(width, height)); styles != nil && styles[c.index] != c.applied {
+			from, to := c.computeStyle(c.applied), c.computeStyle(styles[c.index])
+			c.applied = styles[c.index]
+			w, h := v.Width, v.Height
+			v.restoreStyle(changedStyle(v.saveStyle(), from, to))
+			if !v.hasParent {
+				v.Width, v.Height = w, h
+			}
+			v.Layout()
+		}
+	}
+	for _, child := range v.children {
+		child.item.applyMediaQueries(width, height)
+	}
+}
+
+// computeStyle returns the properties that the declarations of style
+// and those set with SetStyle give to the view.
+func (c *cssState) computeStyle(style string) viewStyle {
+	v := &View{}
+	v.restoreStyle(c.initial)
+	applyStyle(v, style)
+	applyStyle(v, c.style)
+	return v.saveStyle()
+}
+
+// changedStyle returns cur with the properties that differ between from and to set to those of to.
+func changedStyle(cur, from, to viewStyle) viewStyle {
+	c, f, t := reflect.ValueOf(&cur).Elem(), reflect.ValueOf(from), reflect.ValueOf(to)
+	for i := 0; i < c.NumField(); i++ {
+		if !reflect.DeepEqual(f.Field(i).Interface(), t.Field(i).Interface()) {
+			c.Field(i).Set(t.Field(i))
+		}
+	}
+	return cur
+}
+
+// styleDeclarations returns the values of the properties declared in style.
+func styleDeclarations(style string) map[string]string {
+	decls := map[string]string{}
+	for _, pair := range strings.Split(style, ";") {
+		if kv := strings.SplitN(pair, ":", 2); len(kv) == 2 {
+			decls[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+		}
+	}
+	return decls
+}
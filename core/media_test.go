@@ -0,0 +1,191 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMediaQueryMatches(t *testing.T) {
+	for _, tt := range []struct {
+		query  string
+		width  int
+		height int
+		want   bool
+	}{
+		{query: "(max-width: 600px)", width: 600, height: 800, want: true},
+		{query: "(max-width: 600px)", width: 601, height: 800, want: false},
+		{query: "(min-width: 600px)", width: 800, height: 600, want: true},
+		{query: "screen and (min-width: 600px) and (max-width: 900px)", width: 700, height: 600, want: true},
+		{query: "screen and (min-width: 600px) and (max-width: 900px)", width: 901, height: 600, want: false},
+		{query: "(orientation: portrait)", width: 480, height: 800, want: true},
+		{query: "(orientation: landscape)", width: 480, height: 800, want: false},
+		{query: "(min-aspect-ratio: 16/9)", width: 1920, height: 1080, want: true},
+		{query: "(max-aspect-ratio: 4/3)", width: 1920, height: 1080, want: false},
+		{query: "(aspect-ratio: 2)", width: 200, height: 100, want: true},
+		{query: "(max-width: 100px), (orientation: portrait)", width: 480, height: 800, want: true},
+		{query: "not screen and (max-width: 600px)", width: 480, height: 800, want: false},
+		{query: "print", width: 480, height: 800, want: false},
+	} {
+		t.Run(tt.query, func(t *testing.T) {
+			q, err := parseMediaQueryList(tt.query)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, q.matches(tt.width, tt.height))
+		})
+	}
+}
+
+func TestMediaQueryParseError(t *testing.T) {
+	for _, query := range []string{
+		"(max-width: abc)",
+		"(orientation: upside-down)",
+		"(color)",
+		"tv",
+	} {
+		_, err := parseMediaQueryList(query)
+		require.Error(t, err, query)
+	}
+}
+
+func TestParseMediaQueries(t *testing.T) {
+	resetComponents()
+	mock := &mockHandler{}
+	v := Parse(`
+		<head>
+			<style>
+				.container {
+					flex-direction: row;
+				}
+				.item {
+					width: 100px;
+					height: 100px;
+				}
+				@media (orientation: portrait) {
+					.container {
+						flex-direction: column;
+					}
+				}
+				@media (max-width: 400px) {
+					.item {
+						width: 50px;
+						height: 50px;
+					}
+				}
+			</style>
+		</head>
+		<body>
+			<div class="container">
+				<mock-handler id="item" class="item" style="margin-left: 5px"></mock-handler>
+			</div>
+		</body>`, &ParseOptions{
+		Width:  800,
+		Height: 600,
+		Components: map[string]Component{
+			"mock-handler": mock,
+		},
+	})
+
+	item := v.MustGetByID("item")
+	require.Equal(t, Row, v.Direction)
+	require.Equal(t, 100, item.Width)
+	require.Equal(t, 5, item.MarginLeft)
+
+	v.UpdateWithSize(600, 800)
+	require.Equal(t, Column, v.Direction)
+	require.Equal(t, 100, item.Width)
+	require.Equal(t, 600, v.Width)
+	require.Equal(t, 800, v.Height)
+
+	v.UpdateWithSize(300, 800)
+	require.Equal(t, Column, v.Direction)
+	require.Equal(t, 50, item.Width)
+	require.Equal(t, 50, item.Height)
+	require.Equal(t, 5, item.MarginLeft)
+	require.Same(t, mock, item.Handler)
+
+	v.Draw(nil)
+	require.Equal(t, 50, mock.Frame.Dx())
+
+	v.UpdateWithSize(800, 600)
+	require.Equal(t, Row, v.Direction)
+	require.Equal(t, 100, item.Width)
+	require.Equal(t, 5, item.MarginLeft)
+}
+
+func TestMediaQueriesCascade(t *testing.T) {
+	resetComponents()
+	v := Parse(`
+		<head>
+			<style>
+				@media (max-width: 400px) {
+					.item { height: 10px; }
+				}
+				#a { width: 100px; }
+				.item { height: 100px; margin-top: 1px; }
+				@media (max-width: 400px) {
+					.item { width: 50px; margin-top: 5px; }
+				}
+			</style>
+		</head>
+		<body>
+			<div>
+				<div id="a" class="item"></div>
+				<div id="b" class="item" style="margin-top: 2px"></div>
+			</div>
+		</body>`, &ParseOptions{Width: 300, Height: 300})
+
+	a, b := v.MustGetByID("a"), v.MustGetByID("b")
+	require.Equal(t, 100, a.Width, "the id rule is more specific than the @media rule")
+	require.Equal(t, 100, a.Height, "the later rule wins over the earlier @media rule")
+	require.Equal(t, 5, a.MarginTop)
+	require.Equal(t, 50, b.Width)
+	require.Equal(t, 2, b.MarginTop, "the style attribute wins over the @media rule")
+
+	v.UpdateWithSize(500, 300)
+	require.Equal(t, 0, b.Width)
+	require.Equal(t, 1, a.MarginTop)
+}
+
+func TestMediaQueriesKeepRuntimeValues(t *testing.T) {
+	resetComponents()
+	v := Parse(`
+		<head>
+			<style>
+				.item { width: 100px; height: 100px; }
+				@media (max-width: 400px) {
+					.item { width: 50px; }
+				}
+			</style>
+		</head>
+		<body>
+			<div><div id="item" class="item"></div></div>
+		</body>`, &ParseOptions{Width: 800, Height: 600})
+
+	item := v.MustGetByID("item")
+	item.SetHeight(70)
+	item.SetStyle("margin-left: 3")
+	item.SetMarginTop(4)
+	v.UpdateWithSize(300, 600)
+	require.Equal(t, 50, item.Width)
+	require.Equal(t, 70, item.Height)
+	require.Equal(t, 3, item.MarginLeft)
+	require.Equal(t, 4, item.MarginTop)
+
+	item.SetWidth(60)
+	v.UpdateWithSize(200, 600)
+	require.Equal(t, 60, item.Width, "a resize that matches the same rules changes nothing")
+	v.UpdateWithSize(800, 600)
+	require.Equal(t, 100, item.Width)
+	require.Equal(t, 70, item.Height)
+}
+
+func TestMediaQueryWarning(t *testing.T) {
+	var msgs []string
+	prev := warn
+	warn = func(msg string) { msgs = append(msgs, msg) }
+	t.Cleanup(func() { warn = prev })
+
+	_, blocks := splitMediaBlocks(`@media (color) { .a { width: 1px; } } .b { width: 2px; }`)
+	require.Empty(t, blocks)
+	require.Equal(t, []string{"parse media query errors: invalid media feature: color"}, msgs)
+}
@@ -0,0 +1,218 @@
+package core
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+
+	"golang.org/x/image/font"
+)
+
+// DrawOp is the kind of a recorded draw command.
+type DrawOp uint8
+
+const (
+	DrawFillRect DrawOp = iota
+	DrawStrokeRect
+	DrawFillGradient
+	DrawImage
+	DrawText
+	DrawPushClip
+	DrawPopClip
+	DrawBeginLayer
+	DrawEndLayer
+)
+
+func (o DrawOp) String() string {
+	switch o {
+	case DrawFillRect:
+		return "fill"
+	case DrawStrokeRect:
+		return "stroke"
+	case DrawFillGradient:
+		return "gradient"
+	case DrawImage:
+		return "image"
+	case DrawText:
+		return "text"
+	case DrawPushClip:
+		return "clip"
+	case DrawPopClip:
+		return "unclip"
+	case DrawBeginLayer:
+		return "layer"
+	case DrawEndLayer:
+		return "end-layer"
+	}
+	return fmt.Sprintf("unknown draw op: %d", o)
+}
+
+// DrawCommand is a draw call captured by a Recorder.
+// Only the fields of its op are set.
+type DrawCommand struct {
+	Op DrawOp
+	// Rect is the rectangle of a fill, a stroke, a gradient or a clip,
+	// or the destination of an image.
+	Rect image.Rectangle
+	// Src is the source rectangle of an image.
+	Src         image.Rectangle
+	Radius      float32
+	StrokeWidth float32
+	Color       color.Color
+	Gradient    *Gradient
+	Image       Image
+	ImageOpts   ImageOptions
+	Text        string
+	Face        font.Face
+	// X and Y are the start of the baseline of a text.
+	X, Y  int
+	Layer LayerOptions
+}
+
+// String returns the command in the format of Recorder.String.
+func (c DrawCommand) String() string {
+	switch c.Op {
+	case DrawFillRect:
+		return fmt.Sprintf("fill %s r=%g %s", formatRect(c.Rect), c.Radius, formatColor(c.Color))
+	case DrawStrokeRect:
+		return fmt.Sprintf("stroke %s r=%g w=%g %s", formatRect(c.Rect), c.Radius, c.StrokeWidth, formatColor(c.Color))
+	case DrawFillGradient:
+		return fmt.Sprintf("gradient %s r=%g %s", formatRect(c.Rect), c.Radius, formatGradient(c.Gradient))
+	case DrawImage:
+		s := fmt.Sprintf("image %s %s -> %s", imageName(c.Image), formatRect(c.Src), formatRect(c.Rect))
+		if c.ImageOpts.Tint != nil {
+			s += " tint=" + formatColor(c.ImageOpts.Tint)
+		}
+		return s
+	case DrawText:
+		return fmt.Sprintf("text %q at %d,%d %s", c.Text, c.X, c.Y, formatColor(c.Color))
+	case DrawPushClip:
+		return "clip " + formatRect(c.Rect)
+	case DrawEndLayer:
+		return fmt.Sprintf("end-layer alpha=%g", c.Layer.Alpha)
+	}
+	return c.Op.String()
+}
+
+// Recorder is a Renderer that records the draw commands instead of drawing them,
+// so that the drawing of views can be tested without a GPU.
+// Handlers that draw on DrawContext.Screen are not recorded because a Recorder
+// has no target image.
+type Recorder struct {
+	Commands []DrawCommand
+}
+
+// Reset removes the recorded commands.
+func (r *Recorder) Reset() {
+	r.Commands = r.Commands[:0]
+}
+
+// Ops returns the ops of the recorded commands.
+func (r *Recorder) Ops() []DrawOp {
+	ops := make([]DrawOp, len(r.Commands))
+	for i, c := range r.Commands {
+		ops[i] = c.Op
+	}
+	return ops
+}
+
+// String returns the recorded commands, one per line, to be compared with golden files.
+// Images are named by the name they are registered with, or by their size otherwise.
+func (r *Recorder) String() string {
+	var b strings.Builder
+	for _, c := range r.Commands {
+		b.WriteString(c.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+func (r *Recorder) record(c DrawCommand) {
+	r.Commands = append(r.Commands, c)
+}
+
+func (r *Recorder) FillRect(rect image.Rectangle, radius float32, clr color.Color) {
+	r.record(DrawCommand{Op: DrawFillRect, Rect: rect, Radius: radius, Color: clr})
+}
+
+func (r *Recorder) StrokeRect(rect image.Rectangle, radius, width float32, clr color.Color) {
+	r.record(DrawCommand{Op: DrawStrokeRect, Rect: rect, Radius: radius, StrokeWidth: width, Color: clr})
+}
+
+func (r *Recorder) FillGradient(rect image.Rectangle, radius float32, g *Gradient) {
+	r.record(DrawCommand{Op: DrawFillGradient, Rect: rect, Radius: radius, Gradient: g})
+}
+
+func (r *Recorder) DrawImage(img Image, src, dst image.Rectangle, opts *ImageOptions) {
+	c := DrawCommand{Op: DrawImage, Image: img, Src: src, Rect: dst}
+	if opts != nil {
+		c.ImageOpts = *opts
+	}
+	r.record(c)
+}
+
+func (r *Recorder) DrawText(s string, face font.Face, x, y int, clr color.Color) {
+	r.record(DrawCommand{Op: DrawText, Text: s, Face: face, X: x, Y: y, Color: clr})
+}
+
+func (r *Recorder) PushClip(rect image.Rectangle) {
+	r.record(DrawCommand{Op: DrawPushClip, Rect: rect})
+}
+
+func (r *Recorder) PopClip() {
+	r.record(DrawCommand{Op: DrawPopClip})
+}
+
+func (r *Recorder) BeginLayer() {
+	r.record(DrawCommand{Op: DrawBeginLayer})
+}
+
+func (r *Recorder) EndLayer(opts *LayerOptions) {
+	r.record(DrawCommand{Op: DrawEndLayer, Layer: *opts})
+}
+
+func (r *Recorder) Target() Image { return nil }
+
+func formatRect(r image.Rectangle) string {
+	return fmt.Sprintf("(%d,%d)-(%d,%d)", r.Min.X, r.Min.Y, r.Max.X, r.Max.Y)
+}
+
+func formatColor(c color.Color) string {
+	if c == nil {
+		return "none"
+	}
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return fmt.Sprintf("#%02x%02x%02x%02x", n.R, n.G, n.B, n.A)
+}
+
+func formatGradient(g *Gradient) string {
+	var b strings.Builder
+	if g.Kind == GradientRadial {
+		b.WriteString("radial")
+		if g.Circle {
+			b.WriteString(" circle")
+		}
+	} else {
+		fmt.Fprintf(&b, "linear %gdeg", g.Angle)
+	}
+	for _, s := range g.Stops {
+		fmt.Fprintf(&b, " %s@%g", formatColor(s.Color), s.Offset)
+	}
+	return b.String()
+}
+
+// imageName returns the first name in order that the image is registered with, or its size.
+func imageName(img Image) string {
+	found := ""
+	for name, i := range registeredImages {
+		if i == img && (found == "" || name < found) {
+			found = name
+		}
+	}
+	if found != "" {
+		return found
+	}
+	b := img.Bounds()
+	return fmt.Sprintf("%dx%d", b.Dx(), b.Dy())
+}
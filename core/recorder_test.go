@@ -0,0 +1,44 @@
+package core
+
+import (
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+	resetComponents()
+	resetImages()
+	defer resetImages()
+	registerTestFont()
+	RegisterImage("photo", image.NewRGBA(image.Rect(0, 0, 20, 10)))
+
+	v := Parse(`
+		<view style="width: 100; height: 60; align-items: flex-start; background-color: #112233; border: 2px solid #ffffff; border-radius: 4">
+			<view style="width: 40; height: 20; background-image: photo; background-size: cover"></view>
+			<view style="width: 30; height: 20; opacity: 0.5; font-family: mono; font-size: 13; color: #ff0000">Hi</view>
+		</view>`, nil)
+
+	r := &Recorder{}
+	v.Render(r)
+	require.Equal(t, `fill (0,0)-(100,60) r=4 #112233ff
+stroke (0,0)-(100,60) r=4 w=2 #ffffffff
+clip (0,0)-(40,20)
+image photo (0,0)-(20,10) -> (0,0)-(40,20)
+unclip
+layer
+text "Hi" at 40,13 #ff0000ff
+end-layer alpha=0.5
+`, r.String())
+
+	require.Equal(t, []DrawOp{
+		DrawFillRect, DrawStrokeRect, DrawPushClip, DrawImage, DrawPopClip,
+		DrawBeginLayer, DrawText, DrawEndLayer,
+	}, r.Ops())
+
+	r.Reset()
+	require.Empty(t, r.Commands)
+	v.Render(r)
+	require.Len(t, r.Commands, 8)
+}
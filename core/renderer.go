@@ -0,0 +1,121 @@
+package core
+
+import (
+	"image"
+	"image/color"
+
+	"golang.org/x/image/font"
+)
+
+// Image is an image of the engine such as *ebiten.Image.
+// Renderers without an engine can use any image.Image.
+type Image interface {
+	Bounds() image.Rectangle
+}
+
+// Filter is the filter to sample a scaled or transformed image.
+type Filter int
+
+const (
+	FilterNearest Filter = iota
+	FilterLinear
+)
+
+// Renderer draws the boxes, images and text of views.
+// The engine adapter provides the renderer of View.Draw; a Recorder captures the draw commands instead.
+// Rectangles and points are in screen pixels.
+type Renderer interface {
+	// FillRect fills a rectangle with rounded corners.
+	FillRect(rect image.Rectangle, radius float32, clr color.Color)
+	// StrokeRect strokes the inside of a rectangle with rounded corners.
+	StrokeRect(rect image.Rectangle, radius, width float32, clr color.Color)
+	// FillGradient fills a rectangle with rounded corners with a gradient.
+	FillGradient(rect image.Rectangle, radius float32, g *Gradient)
+	// DrawImage draws the src rectangle of the image stretched to the dst rectangle.
+	DrawImage(img Image, src, dst image.Rectangle, opts *ImageOptions)
+	// DrawText draws a line of text whose baseline starts at (x, y).
+	DrawText(s string, face font.Face, x, y int, clr color.Color)
+	// PushClip discards the drawing outside of the rectangle until PopClip is called.
+	PushClip(rect image.Rectangle)
+	PopClip()
+	// BeginLayer starts drawing to a transparent layer until EndLayer composites it
+	// with the options.
+	BeginLayer()
+	EndLayer(opts *LayerOptions)
+	// Target returns the image that handlers draw to. It may be nil.
+	Target() Image
+}
+
+// ImageOptions are the options of Renderer.DrawImage.
+type ImageOptions struct {
+	// Tint multiplies the colors of the image if it is not nil.
+	Tint   color.Color
+	Filter Filter
+}
+
+// LayerOptions are the options to composite a layer.
+type LayerOptions struct {
+	ColorM ColorM
+	GeoM   GeoM
+	Alpha  float64
+	Filter Filter
+}
+
+// ScreenRenderer is a Renderer that draws to a screen image. View.Draw keeps one
+// for each view tree and resets it to the screen of every frame.
+type ScreenRenderer interface {
+	Renderer
+	// Reset makes the renderer draw to the screen.
+	Reset(screen Image)
+}
+
+// CacheRenderer is a Renderer that can draw to offscreen images,
+// which cache the drawing of views with View.Cache.
+type CacheRenderer interface {
+	Renderer
+	// NewOffscreen returns a transparent offscreen image of the size.
+	NewOffscreen(width, height int) Image
+	// DisposeOffscreen releases an offscreen image.
+	DisposeOffscreen(img Image)
+	// Offscreen clears an offscreen image and returns a renderer that draws to it.
+	Offscreen(img Image) Renderer
+}
+
+// Flusher is a Renderer that batches drawing. View.Render flushes the renderer
+// at the end of a view tree and of the drawing of a cache.
+type Flusher interface {
+	// Flush draws what has been batched.
+	Flush()
+}
+
+func flush(r Renderer) {
+	if f, ok := r.(Flusher); ok {
+		f.Flush()
+	}
+}
+
+var newScreenRenderer = func() ScreenRenderer { return &targetRenderer{} }
+
+// SetScreenRenderer sets the function that creates the renderers of View.Draw.
+// The engine adapter sets it. Without it, View.Draw draws nothing but the handlers.
+func SetScreenRenderer(f func() ScreenRenderer) {
+	newScreenRenderer = f
+}
+
+// targetRenderer is the screen renderer when there is no engine.
+// It draws nothing but passes the screen to the handlers.
+type targetRenderer struct {
+	screen Image
+}
+
+func (r *targetRenderer) Reset(screen Image)                                               { r.screen = screen }
+func (r *targetRenderer) Target() Image                                                    { return r.screen }
+func (r *targetRenderer) FillRect(image.Rectangle, float32, color.Color)                   {}
+func (r *targetRenderer) StrokeRect(image.Rectangle, float32, float32, color.Color)        {}
+func (r *targetRenderer) FillGradient(image.Rectangle, float32, *Gradient)                 {}
+func (r *targetRenderer) DrawImage(Image, image.Rectangle, image.Rectangle, *ImageOptions) {}
+func (r *targetRenderer) DrawText(string, font.Face, int, int, color.Color)                {}
+func (r *targetRenderer) PushClip(image.Rectangle)                                         {}
+func (r *targetRenderer) PopClip()                                                         {}
+func (r *targetRenderer) BeginLayer()                                                      {}
+func (r *targetRenderer) EndLayer(*LayerOptions)                                           {}
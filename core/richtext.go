@@ -0,0 +1,392 @@
+package core
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/image/font"
+)
+
+// TextSpan is a run of rich text with its own style.
+// The style of a span is inherited from the view where it is not set.
+type TextSpan struct {
+	Text       string
+	Bold       bool
+	Italic     bool
+	FontFamily string
+	FontSize   int
+	Color      color.Color
+	// Icon is the name of a registered image that is drawn inline instead of the text.
+	// The icon is as tall as the font size and keeps its aspect ratio.
+	Icon string
+}
+
+// SetSpans sets the rich text of the view. Text is set to the plain text of the spans.
+func (v *View) SetSpans(spans []TextSpan) {
+	v.Spans = spans
+	v.Text = spansText(spans)
+	v.Layout()
+}
+
+func spansText(spans []TextSpan) string {
+	var sb strings.Builder
+	for _, s := range spans {
+		sb.WriteString(s.Text)
+	}
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
+
+// textPiece is a run of text without spaces, or an icon, in a line of rich text.
+// The offset and the size are in screen pixels; x is from the start of the line.
+type textPiece struct {
+	text   string
+	face   font.Face
+	color  color.Color
+	icon   Image
+	x      float64
+	width  float64
+	height float64
+	// spaced is true if there is a space before the piece.
+	spaced bool
+}
+
+// textWord is a run of pieces that are not separated by spaces.
+// space is the width of the space before the word.
+type textWord struct {
+	pieces []textPiece
+	width  float64
+	space  float64
+}
+
+// spanWords breaks the spans of the view into words. The line height, the ascent
+// and the descent of the layout are raised to fit the largest font of the spans.
+func (v *View) spanWords(l *textLayout, scale, lineHeight float64) []textWord {
+	var words []textWord
+	var word *textWord
+	space := 0.
+	end := func(face font.Face) {
+		if word != nil {
+			words = append(words, *word)
+			word = nil
+		}
+		space = measureString(face, " ")
+	}
+	add := func(p textPiece) {
+		if word == nil {
+			word = &textWord{space: space}
+		}
+		p.x = word.width
+		word.width += p.width
+		word.pieces = append(word.pieces, p)
+	}
+	family, clr := v.fontFamily(), v.textColor()
+	for _, s := range v.Spans {
+		size := float64(v.fontSize())
+		if s.FontSize > 0 {
+			size = float64(s.FontSize)
+		}
+		size *= scale
+		l.lineHeight = math.Max(l.lineHeight, math.Round(size*lineHeight))
+		if s.Icon != "" {
+			img, ok := GetImage(s.Icon)
+			if ok && img.Bounds().Dy() > 0 {
+				b := img.Bounds()
+				add(textPiece{icon: img, width: size * float64(b.Dx()) / float64(b.Dy()), height: size})
+			}
+			continue
+		}
+		f := family
+		if s.FontFamily != "" {
+			f = s.FontFamily
+		}
+		face := getFontFace(f, size, fontVariantOf(s.Bold, s.Italic))
+		m := face.Metrics()
+		l.ascent = math.Max(l.ascent, float64(m.Ascent)/64)
+		l.descent = math.Max(l.descent, float64(m.Descent)/64)
+		c := clr
+		if s.Color != nil {
+			c = s.Color
+		}
+		if strings.TrimLeftFunc(s.Text, unicode.IsSpace) != s.Text {
+			end(face)
+		}
+		fields := strings.Fields(s.Text)
+		for i, w := range fields {
+			if i > 0 {
+				end(face)
+			}
+			add(textPiece{text: w, face: face, color: c, width: measureString(face, w)})
+		}
+		if len(fields) > 0 && strings.TrimRightFunc(s.Text, unicode.IsSpace) != s.Text {
+			end(face)
+		}
+	}
+	if word != nil {
+		words = append(words, *word)
+	}
+	return words
+}
+
+// wrapSpans puts as many words as fit the width on each line, like wrapWords.
+func wrapSpans(words []textWord, width float64) []textLine {
+	var lines []textLine
+	var line textLine
+	for _, w := range words {
+		x := line.width + w.space
+		if line.pieces == nil || (width > 0 && x+w.width > width) {
+			if line.pieces != nil {
+				lines = append(lines, line)
+			}
+			line, x = textLine{}, 0
+		}
+		for i, p := range w.pieces {
+			p.x += x
+			p.spaced = i == 0 && line.pieces != nil
+			line.pieces = append(line.pieces, p)
+		}
+		line.width = x + w.width
+	}
+	if line.pieces != nil {
+		lines = append(lines, line)
+	}
+	for i := range lines {
+		lines[i].text = piecesText(lines[i].pieces)
+	}
+	return lines
+}
+
+func piecesText(pieces []textPiece) string {
+	var sb strings.Builder
+	for _, p := range pieces {
+		if p.spaced {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(p.text)
+	}
+	return sb.String()
+}
+
+// ellipsizePieces shortens a line of rich text with an ellipsis so that it fits the width.
+// The ellipsis has the style of the last text piece that is kept.
+func (l *textLayout) ellipsizePieces(pieces []textPiece, width float64) textLine {
+	pieces = append([]textPiece(nil), pieces...)
+	for len(pieces) > 0 {
+		last := &pieces[len(pieces)-1]
+		e := textPiece{text: ellipsis, face: l.face, color: l.color, x: last.x + last.width}
+		if last.icon == nil {
+			e.face, e.color = last.face, last.color
+		}
+		e.width = measureString(e.face, ellipsis)
+		if e.x+e.width <= width {
+			pieces = append(pieces, e)
+			return textLine{text: piecesText(pieces), width: e.x + e.width, pieces: pieces}
+		}
+		rs := []rune(last.text)
+		if last.icon != nil || len(rs) <= 1 {
+			pieces = pieces[:len(pieces)-1]
+			continue
+		}
+		last.text = string(rs[:len(rs)-1])
+		last.width = measureString(last.face, last.text)
+	}
+	e := textPiece{text: ellipsis, face: l.face, color: l.color, width: measureString(l.face, ellipsis)}
+	return textLine{text: ellipsis, width: e.width, pieces: []textPiece{e}}
+}
+
+// drawPieces draws a line of rich text that starts at x.
+// Icons are centered vertically on middle and text is drawn on the baseline.
+func drawPieces(r Renderer, pieces []textPiece, x, middle, baseline float64) {
+	for _, p := range pieces {
+		if p.icon != nil {
+			x0, y0 := int(math.Round(x+p.x)), int(math.Round(middle-p.height/2))
+			dst := image.Rect(x0, y0, x0+int(math.Round(p.width)), y0+int(math.Round(p.height)))
+			r.DrawImage(p.icon, p.icon.Bounds(), dst, &ImageOptions{Filter: FilterLinear})
+			continue
+		}
+		r.DrawText(p.text, p.face, int(math.Round(x+p.x)), int(math.Round(baseline)), p.color)
+	}
+}
+
+// inlineTags are the elements that are parsed into the spans of the enclosing view
+// rather than into child views, unless a component has the same name.
+var inlineTags = map[string]func(s *TextSpan, a attrs){
+	"b":      func(s *TextSpan, _ attrs) { s.Bold = true },
+	"strong": func(s *TextSpan, _ attrs) { s.Bold = true },
+	"i":      func(s *TextSpan, _ attrs) { s.Italic = true },
+	"em":     func(s *TextSpan, _ attrs) { s.Italic = true },
+	"span":   func(s *TextSpan, _ attrs) {},
+	"icon":   func(s *TextSpan, a attrs) { s.Icon = a.miscs["src"] },
+}
+
+func isInlineTag(name string, cms cms) bool {
+	if _, ok := inlineTags[name]; !ok {
+		return false
+	}
+	for _, cm := range cms {
+		if _, ok := cm[name]; ok {
+			return false
+		}
+	}
+	return true
+}
+
+// richText collects the spans of a view while it is parsed.
+type richText struct {
+	// styles are the styles of the open inline elements.
+	styles []TextSpan
+	spans  []TextSpan
+	// inline is true once an inline element is found in the view.
+	inline bool
+}
+
+func richTextOf(texts map[*View]*richText, v *View) *richText {
+	r, ok := texts[v]
+	if !ok {
+		r = &richText{}
+		texts[v] = r
+	}
+	return r
+}
+
+func (r *richText) style() TextSpan {
+	if len(r.styles) == 0 {
+		return TextSpan{}
+	}
+	return r.styles[len(r.styles)-1]
+}
+
+// open starts an inline element. An icon is a void element like <img>:
+// it is added as a span and the text after it keeps the enclosing style,
+// since inlining the CSS turns `<icon/>` into an element that wraps the text.
+func (r *richText) open(name string, a attrs, selfClosing bool) {
+	s := r.style()
+	inlineTags[name](&s, a)
+	parseSpanStyle(&s, a.style)
+	r.inline = true
+	if name == "icon" {
+		r.spans = append(r.spans, s)
+		return
+	}
+	if !selfClosing {
+		r.styles = append(r.styles, s)
+	}
+}
+
+func (r *richText) close(name string) {
+	if name != "icon" && len(r.styles) > 0 {
+		r.styles = r.styles[:len(r.styles)-1]
+	}
+}
+
+func (r *richText) text(t string) {
+	s := r.style()
+	s.Text = t
+	r.spans = append(r.spans, s)
+}
+
+// normalizeSpans collapses the white space of the spans as HTML does
+// and drops the spans that are left empty.
+func normalizeSpans(spans []TextSpan) []TextSpan {
+	var out []TextSpan
+	space := true
+	for _, s := range spans {
+		if s.Icon != "" {
+			out = append(out, s)
+			space = false
+			continue
+		}
+		var sb strings.Builder
+		for _, r := range s.Text {
+			if unicode.IsSpace(r) {
+				if !space {
+					sb.WriteByte(' ')
+					space = true
+				}
+				continue
+			}
+			sb.WriteRune(r)
+			space = false
+		}
+		if sb.Len() > 0 {
+			s.Text = sb.String()
+			out = append(out, s)
+		}
+	}
+	if n := len(out); n > 0 && out[n-1].Icon == "" {
+		out[n-1].Text = strings.TrimRight(out[n-1].Text, " ")
+		if out[n-1].Text == "" {
+			out = out[:n-1]
+		}
+	}
+	return out
+}
+
+// parseSpanStyle sets the style attribute of an inline element to the span.
+// Only the properties of text are supported.
+func parseSpanStyle(s *TextSpan, style string) {
+	errs := &ErrorList{}
+	for _, pair := range strings.Split(style, ";") {
+		kv := strings.SplitN(pair, ":", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		k, val := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+		var err error
+		switch k {
+		case "color":
+			var c any
+			if c, err = parseColor(val); err == nil {
+				s.Color = c.(color.Color)
+			}
+		case "font-size":
+			var n any
+			if n, err = parseNumber(val); err == nil {
+				s.FontSize = n.(int)
+			}
+		case "font-family":
+			s.FontFamily = val
+		case "font-weight":
+			s.Bold, err = parseFontWeight(val)
+		case "font-style":
+			s.Italic, err = parseFontStyle(val)
+		default:
+			err = fmt.Errorf("unknown span style: %s", k)
+		}
+		if err != nil {
+			errs.Add(err)
+		}
+	}
+	if errs.HasErrors() {
+		warnf("parse span style errors: %v", errs)
+	}
+}
+
+// parseFontWeight returns true for a bold weight such as `bold` or `700`.
+func parseFontWeight(val string) (bool, error) {
+	switch val {
+	case "normal", "lighter":
+		return false, nil
+	case "bold", "bolder":
+		return true, nil
+	}
+	w, err := strconv.Atoi(val)
+	if err != nil {
+		return false, fmt.Errorf("unknown font-weight: %s", val)
+	}
+	return w >= 600, nil
+}
+
+func parseFontStyle(val string) (bool, error) {
+	switch val {
+	case "normal":
+		return false, nil
+	case "italic", "oblique":
+		return true, nil
+	}
+	return false, fmt.Errorf("unknown font-style: %s", val)
+}
@@ -0,0 +1,103 @@
+package core
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/font/basicfont"
+)
+
+func TestParseRichText(t *testing.T) {
+	registerTestFont()
+	resetComponents()
+	resetImages()
+	RegisterImage("coin", image.NewRGBA(image.Rect(0, 0, 20, 20)))
+
+	v := Parse(`
+		<view style="width: 200; height: 100; flex-direction: column; align-items: flex-start">
+			<view id="msg" style="width: 70; font-family: mono; font-size: 10; line-height: 1">
+				You found <b>3</b>
+				<span style="color: #f00">gold <i>coins</i></span> <icon src="coin"/>!
+			</view>
+			<view id="child"><view>plain</view></view>
+		</view>`, nil)
+
+	msg := v.MustGetByID("msg")
+	red := color.NRGBA{0xff, 0, 0, 0xff}
+	require.Equal(t, []TextSpan{
+		{Text: "You found "},
+		{Text: "3", Bold: true},
+		{Text: " "},
+		{Text: "gold ", Color: red},
+		{Text: "coins", Color: red, Italic: true},
+		{Text: " "},
+		{Icon: "coin"},
+		{Text: "!"},
+	}, msg.Spans)
+	require.Equal(t, "You found 3 gold coins !", msg.Text)
+	require.Len(t, msg.children, 0)
+	require.Nil(t, v.MustGetByID("child").Spans)
+
+	l := msg.layoutText(70, 1)
+	require.Equal(t, []string{"You found", "3 gold", "coins !"}, linesText(l.lines))
+	last := l.lines[2]
+	require.Equal(t, 59., last.width)
+	require.NotNil(t, last.pieces[1].icon)
+	require.Equal(t, 42., last.pieces[1].x)
+	require.Equal(t, 10., last.pieces[1].width)
+	require.Equal(t, red, last.pieces[0].color)
+
+	v.Draw(image.NewRGBA(image.Rect(0, 0, 200, 100)))
+	require.Equal(t, 70, msg.frame.Dx())
+	require.Equal(t, 30, msg.frame.Dy())
+}
+
+func TestRichTextEllipsis(t *testing.T) {
+	registerTestFont()
+	v := &View{FontFamily: "mono", FontSize: 10, LineHeight: 1, WhiteSpace: WhiteSpaceNoWrap, TextOverflow: TextOverflowEllipsis}
+	v.SetSpans([]TextSpan{{Text: "You found "}, {Text: "3", Bold: true}, {Text: " gold"}})
+	require.Equal(t, "You found 3 gold", v.Text)
+
+	lines := v.fitLines(v.layoutText(70, 1), 70, 10)
+	require.Equal(t, []string{"You found…"}, linesText(lines))
+	require.Equal(t, 70., lines[0].width)
+
+	v.SetText("plain")
+	require.Nil(t, v.Spans)
+}
+
+func TestNormalizeSpans(t *testing.T) {
+	spans := normalizeSpans([]TextSpan{
+		{Text: "\n  a  "}, {Text: " ", Bold: true}, {Text: "b\t"}, {Text: "  "},
+	})
+	require.Equal(t, []TextSpan{{Text: "a "}, {Text: "b"}}, spans)
+	require.Empty(t, normalizeSpans([]TextSpan{{Text: "  "}}))
+}
+
+func TestParseSpanStyle(t *testing.T) {
+	s := TextSpan{}
+	parseSpanStyle(&s, "font-weight: 700; font-style: italic; font-size: 20; font-family: mono")
+	require.Equal(t, TextSpan{Bold: true, Italic: true, FontSize: 20, FontFamily: "mono"}, s)
+
+	parseSpanStyle(&s, "font-weight: normal; font-style: normal")
+	require.False(t, s.Bold)
+	require.False(t, s.Italic)
+}
+
+func TestFontVariant(t *testing.T) {
+	registerTestFont()
+	require.Same(t, basicfont.Face7x13, getFontFace("mono", 10, FontBold))
+	bold := getFontFace(DefaultFontFamily, 16, FontBold)
+	require.NotSame(t, GetFontFace(DefaultFontFamily, 16), bold)
+	require.Same(t, bold, getFontFace("unknown", 16, FontBold))
+}
+
+func linesText(lines []textLine) []string {
+	var ss []string
+	for _, l := range lines {
+		ss = append(ss, l.text)
+	}
+	return ss
+}
@@ -0,0 +1,56 @@
+package core
+
+import (
+	"image"
+	"math"
+)
+
+// SetUIScale sets the scale factor of the whole view tree.
+// Layout runs in logical units: the frames passed to the handlers for drawing
+// are multiplied by the scale, and the pointer positions passed to the event
+// handlers are divided by it. A typical scale is ebiten.DeviceScaleFactor()
+// multiplied by a user-facing "UI size" setting.
+func (v *View) SetUIScale(scale float64) {
+	r := v.root()
+	if r.uiScale == scale {
+		return
+	}
+	r.uiScale = scale
+	r.Layout()
+}
+
+// UIScale returns the scale factor of the view tree.
+// It returns 1 if no scale has been set.
+func (v *View) UIScale() float64 {
+	r := v.root()
+	if r.uiScale <= 0 {
+		return 1
+	}
+	return r.uiScale
+}
+
+func (v *View) root() *View {
+	for v.parent != nil {
+		v = v.parent
+	}
+	return v
+}
+
+// toLogicalPoint converts a position in screen pixels to logical units.
+func toLogicalPoint(x, y int, scale float64) (int, int) {
+	if scale == 1 || scale <= 0 {
+		return x, y
+	}
+	return int(math.Floor(float64(x) / scale)), int(math.Floor(float64(y) / scale))
+}
+
+// toPhysicalRect converts a rectangle in logical units to screen pixels.
+func toPhysicalRect(r image.Rectangle, scale float64) image.Rectangle {
+	if scale == 1 || scale <= 0 {
+		return r
+	}
+	return image.Rect(
+		round(float64(r.Min.X)*scale), round(float64(r.Min.Y)*scale),
+		round(float64(r.Max.X)*scale), round(float64(r.Max.Y)*scale),
+	)
+}
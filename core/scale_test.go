@@ -0,0 +1,54 @@
+package core
+
+import (
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUIScale(t *testing.T) {
+	root := &View{
+		Direction:  Row,
+		Justify:    JustifyStart,
+		AlignItems: AlignItemStart,
+	}
+	mock := &mockHandler{}
+	child := &View{
+		Width:      10,
+		Height:     20,
+		MarginLeft: 5,
+		Handler:    mock,
+	}
+	root.AddChild(child)
+
+	require.Equal(t, 1., child.UIScale())
+
+	child.SetUIScale(2)
+	require.Equal(t, 2., root.UIScale())
+
+	root.UpdateWithSize(200, 100)
+	require.Equal(t, 100, root.Width)
+	require.Equal(t, 50, root.Height)
+	require.Equal(t, image.Rect(5, 0, 15, 20), child.frame)
+
+	root.Draw(nil)
+	require.Equal(t, image.Rect(10, 0, 30, 40), mock.Frame)
+}
+
+func TestToLogicalPoint(t *testing.T) {
+	for _, tt := range []struct {
+		x, y         int
+		scale        float64
+		wantX, wantY int
+	}{
+		{x: 10, y: 20, scale: 1, wantX: 10, wantY: 20},
+		{x: 10, y: 21, scale: 2, wantX: 5, wantY: 10},
+		{x: 15, y: 30, scale: 1.5, wantX: 10, wantY: 20},
+		{x: 10, y: 20, scale: 0, wantX: 10, wantY: 20},
+	} {
+		x, y := toLogicalPoint(tt.x, tt.y, tt.scale)
+		require.Equal(t, tt.wantX, x)
+		require.Equal(t, tt.wantY, y)
+	}
+}
@@ -0,0 +1,228 @@
+package core
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+)
+
+// BoxShadow is a shadow painted behind the box of a view.
+// The values are in logical pixels.
+type BoxShadow struct {
+	OffsetX, OffsetY int
+	// Blur is the blur radius. The shadow fades out over about this distance.
+	Blur int
+	// Spread grows the shadow in all directions, or shrinks it if negative.
+	Spread int
+	Color  color.Color
+}
+
+// shadowKey identifies a blurred shadow texture. The values are in screen pixels.
+type shadowKey struct {
+	width, height, radius, blur int
+}
+
+// maxShadowTextures is the number of shadow textures kept.
+// The least recently used texture is removed to add another one.
+const maxShadowTextures = 64
+
+type shadowTexture struct {
+	img     *image.RGBA
+	lastUse int
+}
+
+var (
+	shadowTextures = map[shadowKey]*shadowTexture{}
+	shadowUses     int
+)
+
+// drawBoxShadows paints the shadows of the view around the frame.
+// The frame is in screen pixels.
+func (v *View) drawBoxShadows(r Renderer, frame image.Rectangle) {
+	scale := v.UIScale()
+	px := func(n int) int { return int(math.Round(float64(n) * scale)) }
+	for i := len(v.BoxShadows) - 1; i >= 0; i-- {
+		s := v.BoxShadows[i]
+		if s.Color == nil {
+			continue
+		}
+		spread, blur := px(s.Spread), px(s.Blur)
+		shape := frame.Inset(-spread).Add(image.Pt(px(s.OffsetX), px(s.OffsetY)))
+		if shape.Dx() <= 0 || shape.Dy() <= 0 {
+			continue
+		}
+		radius := 0
+		if v.BorderRadius > 0 {
+			radius = int(math.Max(float64(px(v.BorderRadius)+spread), 0))
+		}
+		key := shadowKey{shape.Dx(), shape.Dy(), radius, blur}
+		// Larger shapes stretch the middle row and column of the smallest texture
+		// whose middle is not affected by the corners, so resizing does not blur again.
+		k := shadowSliceSize(radius, blur)
+		key.width, key.height = minInt(key.width, 2*k+1), minInt(key.height, 2*k+1)
+		img := getShadowTexture(key)
+		opts := &ImageOptions{Tint: s.Color}
+		for _, sx := range shadowSlices(key.width, shape.Dx(), k, blur) {
+			for _, sy := range shadowSlices(key.height, shape.Dy(), k, blur) {
+				src := image.Rect(sx[0], sy[0], sx[1], sy[1])
+				dst := image.Rect(sx[2], sy[2], sx[3], sy[3]).Add(image.Pt(shape.Min.X-blur, shape.Min.Y-blur))
+				r.DrawImage(img, src, dst, opts)
+			}
+		}
+	}
+}
+
+// shadowSliceSize returns the distance from the sides of a shadow
+// beyond which the shadow is not affected by the corners and the sides.
+func shadowSliceSize(radius, blur int) int {
+	// The three box blurs of shadowMask spread a side by up to 1.5 times the blur radius.
+	return radius + 2*blur + 2
+}
+
+// shadowSlices returns the source and the destination ranges of the texture
+// of the given size to draw a shadow of the size n along an axis.
+func shadowSlices(size, n, k, blur int) [][4]int {
+	end := size + 2*blur
+	if size == n {
+		return [][4]int{{0, end, 0, end}}
+	}
+	c := k + blur
+	return [][4]int{{0, c, 0, c}, {c, c + 1, c, c + n - 2*k}, {c + 1, end, c + n - 2*k, n + 2*blur}}
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// boxShadowBounds returns the frame extended by the shadows of the view.
+func (v *View) boxShadowBounds(frame image.Rectangle) image.Rectangle {
+	scale := v.UIScale()
+	px := func(n int) int { return int(math.Round(float64(n) * scale)) }
+	b := frame
+	for _, s := range v.BoxShadows {
+		r := frame.Inset(-px(s.Spread) - px(s.Blur)).Add(image.Pt(px(s.OffsetX), px(s.OffsetY)))
+		b = b.Union(r)
+	}
+	return b
+}
+
+// getShadowTexture returns the cached white texture of a blurred rounded rectangle.
+// The texture has a margin of the blur radius around the rectangle.
+func getShadowTexture(key shadowKey) *image.RGBA {
+	shadowUses++
+	if t, ok := shadowTextures[key]; ok {
+		t.lastUse = shadowUses
+		return t.img
+	}
+	if len(shadowTextures) >= maxShadowTextures {
+		var oldest shadowKey
+		lastUse := shadowUses
+		for k, t := range shadowTextures {
+			if t.lastUse < lastUse {
+				oldest, lastUse = k, t.lastUse
+			}
+		}
+		delete(shadowTextures, oldest)
+	}
+	alpha := shadowMask(key)
+	img := image.NewRGBA(image.Rect(0, 0, key.width+2*key.blur, key.height+2*key.blur))
+	for i, a := range alpha {
+		b := byte(math.Round(a * 0xff))
+		img.Pix[i*4], img.Pix[i*4+1], img.Pix[i*4+2], img.Pix[i*4+3] = b, b, b, b
+	}
+	shadowTextures[key] = &shadowTexture{img: img, lastUse: shadowUses}
+	return img
+}
+
+// shadowMask returns the alpha values of a blurred rounded rectangle
+// with a margin of the blur radius.
+func shadowMask(key shadowKey) []float64 {
+	w, h := key.width+2*key.blur, key.height+2*key.blur
+	alpha := make([]float64, w*h)
+	hw, hh := float64(key.width)/2, float64(key.height)/2
+	r := math.Min(float64(key.radius), math.Min(hw, hh))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			// The signed distance from the pixel center to the rounded rectangle.
+			dx := math.Abs(float64(x-key.blur)+.5-hw) - (hw - r)
+			dy := math.Abs(float64(y-key.blur)+.5-hh) - (hh - r)
+			d := math.Hypot(math.Max(dx, 0), math.Max(dy, 0)) + math.Min(math.Max(dx, dy), 0) - r
+			alpha[y*w+x] = math.Max(0, math.Min(1, .5-d))
+		}
+	}
+	// Three box blurs approximate a gaussian blur with a standard deviation of half the blur radius.
+	if br := int(math.Round(float64(key.blur) / 2)); br > 0 {
+		tmp := make([]float64, len(alpha))
+		for i := 0; i < 3; i++ {
+			boxBlur(tmp, alpha, w, h, br, 1, w)
+			boxBlur(alpha, tmp, h, w, br, w, 1)
+		}
+	}
+	return alpha
+}
+
+// boxBlur blurs n lines of length l from src to dst with a box of radius r.
+// step is the distance between two pixels of a line and stride between two lines.
+func boxBlur(dst, src []float64, l, n, r, step, stride int) {
+	size := float64(2*r + 1)
+	for line := 0; line < n; line++ {
+		base := line * stride
+		at := func(i int) float64 {
+			if i < 0 || i >= l {
+				return 0
+			}
+			return src[base+i*step]
+		}
+		sum := 0.
+		for i := -r; i <= r; i++ {
+			sum += at(i)
+		}
+		for i := 0; i < l; i++ {
+			dst[base+i*step] = sum / size
+			sum += at(i+r+1) - at(i-r)
+		}
+	}
+}
+
+// parseBoxShadow parses the CSS box-shadow property such as
+// `0 4px 8px rgba(0, 0, 0, 0.5), 0 0 0 1px #fff`.
+func parseBoxShadow(val string) (any, error) {
+	if val == "none" {
+		return []BoxShadow(nil), nil
+	}
+	var shadows []BoxShadow
+	for _, part := range splitValues(val, ',') {
+		s := BoxShadow{Color: color.Black}
+		var lengths []int
+		for _, f := range splitValues(part, ' ') {
+			if n, err := parseNumber(f); err == nil {
+				lengths = append(lengths, n.(int))
+				continue
+			}
+			c, err := parseColor(f)
+			if err != nil {
+				return nil, fmt.Errorf("invalid box-shadow: %s", part)
+			}
+			s.Color = c.(color.Color)
+		}
+		if len(lengths) < 2 || len(lengths) > 4 {
+			return nil, fmt.Errorf("invalid box-shadow: %s", part)
+		}
+		s.OffsetX, s.OffsetY = lengths[0], lengths[1]
+		if len(lengths) > 2 {
+			s.Blur = lengths[2]
+			if s.Blur < 0 {
+				return nil, fmt.Errorf("invalid box-shadow: %s", part)
+			}
+		}
+		if len(lengths) > 3 {
+			s.Spread = lengths[3]
+		}
+		shadows = append(shadows, s)
+	}
+	return shadows, nil
+}
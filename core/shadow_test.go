@@ -0,0 +1,97 @@
+package core
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBoxShadow(t *testing.T) {
+	got, err := parseBoxShadow("0 4px 8px rgba(0, 0, 0, 0.5), 2px -2px 0 1px red, 1 1")
+	require.NoError(t, err)
+	require.Equal(t, []BoxShadow{
+		{OffsetY: 4, Blur: 8, Color: color.NRGBA{0, 0, 0, 0x80}},
+		{OffsetX: 2, OffsetY: -2, Spread: 1, Color: color.RGBA{0xff, 0, 0, 0xff}},
+		{OffsetX: 1, OffsetY: 1, Color: color.Black},
+	}, got)
+
+	got, err = parseBoxShadow("none")
+	require.NoError(t, err)
+	require.Nil(t, got)
+
+	for _, val := range []string{"1px", "1 2 3 4 5", "1 2 -3", "inset 1 2", "1 2 foo"} {
+		_, err := parseBoxShadow(val)
+		require.Error(t, err, val)
+	}
+}
+
+func TestShadowMask(t *testing.T) {
+	at := func(alpha []float64, w, x, y int) float64 { return alpha[y*w+x] }
+
+	sharp := shadowMask(shadowKey{width: 10, height: 6})
+	require.Len(t, sharp, 60)
+	require.Equal(t, 1., at(sharp, 10, 0, 0))
+	require.Equal(t, 1., at(sharp, 10, 9, 5))
+
+	blurred := shadowMask(shadowKey{width: 20, height: 20, blur: 6})
+	w := 32
+	require.Len(t, blurred, w*w)
+	require.InDelta(t, 1, at(blurred, w, 16, 16), 1e-9)
+	require.Less(t, at(blurred, w, 0, 16), at(blurred, w, 6, 16))
+	require.Less(t, at(blurred, w, 6, 16), at(blurred, w, 12, 16))
+	require.InDelta(t, at(blurred, w, 3, 16), at(blurred, w, 28, 16), 1e-9)
+
+	rounded := shadowMask(shadowKey{width: 20, height: 20, radius: 10})
+	require.Equal(t, 0., at(rounded, 20, 0, 0))
+	require.InDelta(t, 1, at(rounded, 20, 10, 0), .05)
+}
+
+func TestShadowTextureCache(t *testing.T) {
+	key := shadowKey{width: 8, height: 8, blur: 2}
+	img := getShadowTexture(key)
+	require.Same(t, img, getShadowTexture(key))
+	require.Equal(t, image.Rect(0, 0, 12, 12), img.Bounds())
+
+	// The least recently used textures are removed first.
+	for i := 0; i < maxShadowTextures; i++ {
+		getShadowTexture(shadowKey{width: 1, height: i + 1})
+		getShadowTexture(key)
+	}
+	require.Len(t, shadowTextures, maxShadowTextures)
+	require.Same(t, img, getShadowTexture(key))
+	require.NotContains(t, shadowTextures, shadowKey{width: 1, height: 1})
+}
+
+func TestShadowSlices(t *testing.T) {
+	r := &Recorder{}
+	v := &View{BoxShadows: []BoxShadow{{Blur: 2, Color: color.Black}}}
+	v.drawBoxShadows(r, image.Rect(0, 0, 100, 10))
+	v.drawBoxShadows(r, image.Rect(0, 0, 120, 10))
+
+	// The width is stretched from the middle column of the same texture.
+	require.Len(t, r.Commands, 6)
+	require.Equal(t, "image 17x14 (0,0)-(8,14) -> (-2,-2)-(6,12) tint=#000000ff", r.Commands[0].String())
+	require.Equal(t, "image 17x14 (8,0)-(9,14) -> (6,-2)-(94,12) tint=#000000ff", r.Commands[1].String())
+	require.Equal(t, "image 17x14 (9,0)-(17,14) -> (94,-2)-(102,12) tint=#000000ff", r.Commands[2].String())
+	require.Equal(t, "image 17x14 (9,0)-(17,14) -> (114,-2)-(122,12) tint=#000000ff", r.Commands[5].String())
+	require.Same(t, r.Commands[0].Image, r.Commands[3].Image)
+}
+
+func TestBoxShadow(t *testing.T) {
+	resetComponents()
+	v := Parse(`
+		<view style="width: 100; height: 100">
+			<view id="card" style="width: 40; height: 20; box-shadow: 0 4px 8px #0008"></view>
+		</view>`, nil)
+
+	card := v.MustGetByID("card")
+	require.Len(t, card.BoxShadows, 1)
+	require.True(t, card.shouldPaintBox())
+	require.Equal(t, image.Rect(-8, -4, 48, 32), card.boxShadowBounds(image.Rect(0, 0, 40, 20)))
+
+	v.SetUIScale(2)
+	require.Equal(t, image.Rect(-16, -8, 96, 64), card.boxShadowBounds(image.Rect(0, 0, 80, 40)))
+	v.Draw(image.NewRGBA(image.Rect(0, 0, 200, 200)))
+}
@@ -0,0 +1,386 @@
+package core
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strings"
+
+	"golang.org/x/image/font"
+)
+
+// TextAlign is the 'text-align' property.
+type TextAlign uint8
+
+const (
+	TextAlignLeft TextAlign = iota
+	TextAlignCenter
+	TextAlignRight
+)
+
+func (t TextAlign) String() string {
+	switch t {
+	case TextAlignLeft:
+		return "left"
+	case TextAlignCenter:
+		return "center"
+	case TextAlignRight:
+		return "right"
+	}
+	return fmt.Sprintf("unknown text-align: %d", t)
+}
+
+// WhiteSpace is the 'white-space' property.
+type WhiteSpace uint8
+
+const (
+	// WhiteSpaceNormal wraps the text at word boundaries to fit the width of the view.
+	WhiteSpaceNormal WhiteSpace = iota
+	// WhiteSpaceNoWrap keeps the text on a single line.
+	WhiteSpaceNoWrap
+)
+
+func (w WhiteSpace) String() string {
+	switch w {
+	case WhiteSpaceNormal:
+		return "normal"
+	case WhiteSpaceNoWrap:
+		return "nowrap"
+	}
+	return fmt.Sprintf("unknown white-space: %d", w)
+}
+
+// TextOverflow is the 'text-overflow' property.
+type TextOverflow uint8
+
+const (
+	// TextOverflowClip draws the text that does not fit as it is.
+	TextOverflowClip TextOverflow = iota
+	// TextOverflowEllipsis ends the last line that fits with an ellipsis.
+	TextOverflowEllipsis
+)
+
+func (t TextOverflow) String() string {
+	switch t {
+	case TextOverflowClip:
+		return "clip"
+	case TextOverflowEllipsis:
+		return "ellipsis"
+	}
+	return fmt.Sprintf("unknown text-overflow: %d", t)
+}
+
+const (
+	defaultLineHeight = 1.2
+	ellipsis          = "…"
+)
+
+// SetText sets the text of the view and clears its spans.
+func (v *View) SetText(text string) {
+	v.Text = text
+	v.Spans = nil
+	v.Layout()
+}
+
+// SetFontFamily sets the font family of the view and the descendants that have none.
+func (v *View) SetFontFamily(family string) {
+	v.FontFamily = family
+	v.Layout()
+}
+
+// SetFontSize sets the font size of the view and the descendants that have none.
+func (v *View) SetFontSize(size int) {
+	v.FontSize = size
+	v.Layout()
+}
+
+// SetColor sets the text color of the view and the descendants that have none.
+func (v *View) SetColor(c color.Color) {
+	v.Color = c
+	v.Invalidate()
+}
+
+func (v *View) fontFamily() string {
+	for p := v; p != nil; p = p.parent {
+		if p.FontFamily != "" {
+			return p.FontFamily
+		}
+	}
+	return DefaultFontFamily
+}
+
+func (v *View) fontSize() int {
+	for p := v; p != nil; p = p.parent {
+		if p.FontSize > 0 {
+			return p.FontSize
+		}
+	}
+	return DefaultFontSize
+}
+
+func (v *View) textColor() color.Color {
+	for p := v; p != nil; p = p.parent {
+		if p.Color != nil {
+			return p.Color
+		}
+	}
+	return color.Black
+}
+
+// paintsText returns true if the view draws its text itself
+// rather than leaving it to its handler.
+func (v *View) paintsText() bool {
+	if v.Text == "" && len(v.Spans) == 0 {
+		return false
+	}
+	if v.Handler == nil {
+		return true
+	}
+	h, ok := v.Handler.(TextPaintHandler)
+	return ok && h.PaintsText()
+}
+
+func (v *View) shouldPaintText() bool {
+	return !v.Hidden && v.Display != DisplayNone && v.paintsText()
+}
+
+// textLine is a line of text and its width in screen pixels.
+// The pieces of a line of rich text are drawn instead of its text.
+type textLine struct {
+	text   string
+	width  float64
+	pieces []textPiece
+}
+
+// textLayout is the text of a view broken into lines.
+// The ascent and the descent are the largest ones of the faces in the lines.
+type textLayout struct {
+	face            font.Face
+	color           color.Color
+	lines           []textLine
+	lineHeight      float64
+	ascent, descent float64
+}
+
+// layoutText breaks the text of the view into lines that fit the width in screen pixels.
+// The text is not wrapped if the width is 0 or the view does not wrap.
+func (v *View) layoutText(width, scale float64) *textLayout {
+	size := float64(v.fontSize()) * scale
+	lineHeight := v.LineHeight
+	if lineHeight <= 0 {
+		lineHeight = defaultLineHeight
+	}
+	l := &textLayout{
+		face:       GetFontFace(v.fontFamily(), size),
+		color:      v.textColor(),
+		lineHeight: math.Round(size * lineHeight),
+	}
+	m := l.face.Metrics()
+	l.ascent, l.descent = float64(m.Ascent)/64, float64(m.Descent)/64
+	if v.WhiteSpace == WhiteSpaceNoWrap {
+		width = 0
+	}
+	if len(v.Spans) > 0 {
+		l.lines = wrapSpans(v.spanWords(l, scale, lineHeight), width)
+		return l
+	}
+	l.lines = wrapWords(l.face, strings.Fields(v.Text), width)
+	return l
+}
+
+// wrapWords puts as many words as fit the width on each line.
+// A word wider than the width is put on its own line.
+func wrapWords(face font.Face, words []string, width float64) []textLine {
+	var lines []textLine
+	var line textLine
+	space := measureString(face, " ")
+	for _, w := range words {
+		ww := measureString(face, w)
+		if line.text == "" {
+			line = textLine{text: w, width: ww}
+			continue
+		}
+		if width > 0 && line.width+space+ww > width {
+			lines = append(lines, line)
+			line = textLine{text: w, width: ww}
+			continue
+		}
+		line.text += " " + w
+		line.width += space + ww
+	}
+	if line.text != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func measureString(face font.Face, s string) float64 {
+	return float64(font.MeasureString(face, s)) / 64
+}
+
+// ellipsize shortens s with an ellipsis so that it fits the width.
+func ellipsize(face font.Face, s string, width float64) textLine {
+	rs := []rune(s)
+	for len(rs) > 0 {
+		t := strings.TrimRight(string(rs), " ") + ellipsis
+		if w := measureString(face, t); w <= width {
+			return textLine{text: t, width: w}
+		}
+		rs = rs[:len(rs)-1]
+	}
+	return textLine{text: ellipsis, width: measureString(face, ellipsis)}
+}
+
+func (l *textLayout) ellipsize(line textLine, width float64) textLine {
+	if line.pieces != nil {
+		return l.ellipsizePieces(line.pieces, width)
+	}
+	return ellipsize(l.face, line.text, width)
+}
+
+// fitLines applies the text-overflow of the view to the lines for a box of the given size.
+func (v *View) fitLines(l *textLayout, width, height float64) []textLine {
+	lines := l.lines
+	if v.TextOverflow != TextOverflowEllipsis || len(lines) == 0 {
+		return lines
+	}
+	maxLines := int(math.Max(1, math.Floor(height/l.lineHeight)))
+	if len(lines) > maxLines {
+		lines = append([]textLine(nil), lines[:maxLines]...)
+		last := &lines[maxLines-1]
+		*last = l.ellipsize(*last, width)
+	}
+	for i := range lines {
+		if lines[i].width > width {
+			lines = append([]textLine(nil), lines...)
+			lines[i] = l.ellipsize(lines[i], width)
+		}
+	}
+	return lines
+}
+
+// measureText returns the size of the text in logical pixels when it is wrapped at the width.
+func (v *View) measureText(width int) (int, int) {
+	scale := v.UIScale()
+	l := v.layoutText(float64(width)*scale, scale)
+	w := 0.
+	for _, line := range l.lines {
+		w = math.Max(w, line.width)
+	}
+	h := float64(len(l.lines)) * l.lineHeight
+	return int(math.Ceil(w / scale)), int(math.Ceil(h / scale))
+}
+
+// sizeToText makes the size of the view at least the size of its text
+// on the axes where the size is not set.
+func (v *View) sizeToText() {
+	wrapWidth := 0
+	if v.Width != 0 {
+		wrapWidth = v.Width
+	} else if v.WidthInPct != 0 && v.hasParent {
+		wrapWidth = int(float64(v.parent.width()) * v.WidthInPct / 100)
+	}
+	w, h := v.measureText(wrapWidth)
+	if !v.isWidthFixed() && w > v.calculatedWidth {
+		v.calculatedWidth = w
+	}
+	if !v.isHeightFixed() && h > v.calculatedHeight {
+		v.calculatedHeight = h
+	}
+}
+
+// textLayoutKey is what the cached text layout of a view depends on,
+// besides the spans that are only changed with Layout.
+type textLayoutKey struct {
+	width, scale float64
+	text         string
+	family       string
+	size         int
+	lineHeight   float64
+	whiteSpace   WhiteSpace
+	color        color.Color
+}
+
+// cachedTextLayout returns the text layout of the view for the width in screen pixels.
+// The layout is reused until the key changes or the view is laid out again.
+func (v *View) cachedTextLayout(width, scale float64) *textLayout {
+	key := textLayoutKey{
+		width:      width,
+		scale:      scale,
+		text:       v.Text,
+		family:     v.fontFamily(),
+		size:       v.fontSize(),
+		lineHeight: v.LineHeight,
+		whiteSpace: v.WhiteSpace,
+		color:      v.textColor(),
+	}
+	if v.textLayout == nil || v.textLayoutKey != key {
+		v.textLayout = v.layoutText(width, scale)
+		v.textLayoutKey = key
+	}
+	return v.textLayout
+}
+
+// paintText draws the text of the view inside the frame in screen pixels.
+func (v *View) paintText(r Renderer, frame image.Rectangle) {
+	l := v.cachedTextLayout(float64(frame.Dx()), v.UIScale())
+	lines := v.fitLines(l, float64(frame.Dx()), float64(frame.Dy()))
+	for i, line := range lines {
+		top := float64(frame.Min.Y) + float64(i)*l.lineHeight
+		baseline := top + (l.lineHeight-(l.ascent+l.descent))/2 + l.ascent
+		x := float64(frame.Min.X)
+		switch v.TextAlign {
+		case TextAlignCenter:
+			x += (float64(frame.Dx()) - line.width) / 2
+		case TextAlignRight:
+			x += float64(frame.Dx()) - line.width
+		}
+		if line.pieces != nil {
+			drawPieces(r, line.pieces, x, top+l.lineHeight/2, baseline)
+			continue
+		}
+		r.DrawText(line.text, l.face, int(math.Round(x)), int(math.Round(baseline)), l.color)
+	}
+}
+
+func parseTextAlign(val string) (any, error) {
+	switch val {
+	case "left", "start":
+		return TextAlignLeft, nil
+	case "center":
+		return TextAlignCenter, nil
+	case "right", "end":
+		return TextAlignRight, nil
+	}
+	return nil, fmt.Errorf("unknown text-align: %s", val)
+}
+
+func parseWhiteSpace(val string) (any, error) {
+	switch val {
+	case "normal":
+		return WhiteSpaceNormal, nil
+	case "nowrap":
+		return WhiteSpaceNoWrap, nil
+	}
+	return nil, fmt.Errorf("unknown white-space: %s", val)
+}
+
+func parseTextOverflow(val string) (any, error) {
+	switch val {
+	case "clip":
+		return TextOverflowClip, nil
+	case "ellipsis":
+		return TextOverflowEllipsis, nil
+	}
+	return nil, fmt.Errorf("unknown text-overflow: %s", val)
+}
+
+// parseLineHeight parses a multiple of the font size such as `1.5` or `150%`.
+// `normal` is 0, which uses the default line height.
+func parseLineHeight(val string) (any, error) {
+	if val == "normal" {
+		return 0., nil
+	}
+	return parseFraction(val)
+}
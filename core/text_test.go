@@ -0,0 +1,118 @@
+package core
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/font/basicfont"
+)
+
+// basicfont.Face7x13 advances 7px per glyph and has a 13px line.
+func registerTestFont() {
+	resetFonts()
+	RegisterFontFace("mono", basicfont.Face7x13)
+}
+
+func TestWrapWords(t *testing.T) {
+	face := basicfont.Face7x13
+	lines := wrapWords(face, []string{"aaa", "bb", "c", "dddddd"}, 49)
+	require.Equal(t, []textLine{
+		{text: "aaa bb", width: 42},
+		{text: "c", width: 7},
+		{text: "dddddd", width: 42},
+	}, lines)
+
+	lines = wrapWords(face, []string{"aaa", "bb"}, 0)
+	require.Equal(t, []textLine{{text: "aaa bb", width: 42}}, lines)
+	require.Empty(t, wrapWords(face, nil, 10))
+}
+
+func TestEllipsis(t *testing.T) {
+	registerTestFont()
+	v := &View{Text: "hello brave new world", FontFamily: "mono", FontSize: 10, LineHeight: 1, TextOverflow: TextOverflowEllipsis}
+	l := v.layoutText(70, 1)
+	require.Len(t, l.lines, 3)
+
+	lines := v.fitLines(l, 70, 25)
+	require.Len(t, lines, 2)
+	require.Equal(t, "hello", lines[0].text)
+	require.Equal(t, "brave new…", lines[1].text)
+
+	v.WhiteSpace = WhiteSpaceNoWrap
+	lines = v.fitLines(v.layoutText(70, 1), 70, 25)
+	require.Equal(t, []textLine{{text: "hello bra…", width: 70}}, lines)
+}
+
+func TestTextSizesView(t *testing.T) {
+	registerTestFont()
+	resetComponents()
+	v := Parse(`
+		<view style="width: 300; height: 300; flex-direction: column; align-items: flex-start; font-family: mono, serif; font-size: 13">
+			<view id="label">Hello world</view>
+			<view id="para" style="width: 50; line-height: 2">one two three</view>
+			<view id="fixed" style="height: 10; color: #f00">fixed</view>
+		</view>`, nil)
+	v.Draw(image.NewRGBA(image.Rect(0, 0, 300, 300)))
+
+	label := v.MustGetByID("label")
+	require.Equal(t, "mono, serif", label.fontFamily())
+	require.Equal(t, image.Rect(0, 0, 77, 16), label.frame)
+
+	para := v.MustGetByID("para")
+	require.Equal(t, 50, para.frame.Dx())
+	require.Equal(t, 2*26, para.frame.Dy())
+
+	fixed := v.MustGetByID("fixed")
+	require.Equal(t, 35, fixed.frame.Dx())
+	require.Equal(t, 10, fixed.frame.Dy())
+	require.Equal(t, color.NRGBA{0xff, 0, 0, 0xff}, fixed.textColor())
+	require.Equal(t, color.Black, label.textColor())
+}
+
+func TestTextLayoutCache(t *testing.T) {
+	registerTestFont()
+	root := &View{Width: 100, Height: 100, FontFamily: "mono"}
+	label := &View{Text: "Hello world"}
+	root.AddChild(label)
+	r := &Recorder{}
+	root.Render(r)
+	l := label.textLayout
+	require.NotNil(t, l)
+	root.Render(r)
+	require.Same(t, l, label.textLayout, "the layout is reused while nothing changes")
+
+	root.FontSize = 20
+	root.Render(r)
+	require.NotSame(t, l, label.textLayout, "an inherited style changes the key")
+
+	l = label.textLayout
+	label.SetText("Bye")
+	require.Nil(t, label.textLayout)
+	root.Render(r)
+	require.NotSame(t, l, label.textLayout)
+	require.Equal(t, "Bye", label.textLayout.lines[0].text)
+}
+
+func TestParseTextStyle(t *testing.T) {
+	resetComponents()
+	v := Parse(`
+		<view id="v" style="text-align: center; white-space: nowrap; text-overflow: ellipsis; line-height: 150%">Hi</view>`, nil)
+	require.Equal(t, TextAlignCenter, v.TextAlign)
+	require.Equal(t, WhiteSpaceNoWrap, v.WhiteSpace)
+	require.Equal(t, TextOverflowEllipsis, v.TextOverflow)
+	require.Equal(t, 1.5, v.LineHeight)
+	require.True(t, v.paintsText())
+
+	v.Handler = &mockHandler{}
+	require.False(t, v.paintsText())
+}
+
+func TestDefaultFont(t *testing.T) {
+	resetFonts()
+	face := GetFontFace("unknown", 16)
+	require.NotNil(t, face)
+	require.Same(t, face, GetFontFace(DefaultFontFamily, 16))
+	require.Error(t, RegisterFont("broken", []byte("not a font")))
+}
@@ -0,0 +1,276 @@
+package core
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Transform is a 2D transform of a view and its descendants.
+// The view is scaled, then rotated around the origin, then translated.
+// It does not affect the layout. The zero value is the identity.
+type Transform struct {
+	// TranslateX and TranslateY are the translation in logical pixels.
+	TranslateX, TranslateY float64
+	// ScaleOffsetX and ScaleOffsetY are the scale minus 1, so that 0 keeps the size,
+	// 1 doubles it and -1 shrinks the view to nothing. See Scale and SetScale.
+	ScaleOffsetX, ScaleOffsetY float64
+	// Rotate is the clockwise rotation in radians.
+	Rotate float64
+	// OriginX and OriginY are the origin of the scale and rotation
+	// as a fraction of the view's frame. NewTransform and CSS put it
+	// at the center (0.5, 0.5); the zero value is the top-left corner.
+	OriginX, OriginY float64
+}
+
+// NewTransform returns the identity transform with the origin at the center of the view.
+func NewTransform() *Transform {
+	return &Transform{OriginX: 0.5, OriginY: 0.5}
+}
+
+// Scale returns the scale of the transform.
+func (t *Transform) Scale() (x, y float64) {
+	return 1 + t.ScaleOffsetX, 1 + t.ScaleOffsetY
+}
+
+// SetScale sets the scale of the transform.
+func (t *Transform) SetScale(x, y float64) {
+	t.ScaleOffsetX, t.ScaleOffsetY = x-1, y-1
+}
+
+func (t *Transform) isIdentity() bool {
+	return t == nil || (t.TranslateX == 0 && t.TranslateY == 0 &&
+		t.ScaleOffsetX == 0 && t.ScaleOffsetY == 0 && t.Rotate == 0)
+}
+
+// geoM returns the matrix of the transform for a view with the given frame.
+// The translation is multiplied by scale to convert it to the frame's pixels.
+func (t *Transform) geoM(frame image.Rectangle, scale float64) GeoM {
+	ox := float64(frame.Min.X) + t.OriginX*float64(frame.Dx())
+	oy := float64(frame.Min.Y) + t.OriginY*float64(frame.Dy())
+	g := GeoM{}
+	g.Translate(-ox, -oy)
+	g.Scale(t.Scale())
+	g.Rotate(t.Rotate)
+	g.Translate(ox+t.TranslateX*scale, oy+t.TranslateY*scale)
+	return g
+}
+
+// SetTransform sets the transform of the view and its descendants.
+// Passing nil removes the transform.
+func (v *View) SetTransform(t *Transform) {
+	v.Transform = t
+	v.invalidateParent()
+}
+
+// hasTransform returns true if the view is drawn transformed by its transform
+// or its layout animation.
+func (v *View) hasTransform() bool {
+	return !v.Transform.isIdentity() || v.layoutFlip != nil
+}
+
+// geoM returns the matrix of the transform and the layout animation of the view
+// for the given frame in pixels of the scale.
+func (v *View) geoM(frame image.Rectangle, scale float64) GeoM {
+	g := GeoM{}
+	if !v.Transform.isIdentity() {
+		g = v.Transform.geoM(frame, scale)
+	}
+	if v.layoutFlip != nil {
+		g.Concat(v.layoutFlip.geoM(v.frame, scale))
+	}
+	return g
+}
+
+// screenGeoM returns the matrix that maps the untransformed pixels of the view to the
+// screen through the transforms of the view and its ancestors, and false if there are none.
+func (v *View) screenGeoM() (GeoM, bool) {
+	scale := v.UIScale()
+	g, ok := GeoM{}, false
+	for p := v; p != nil; p = p.parent {
+		if p.hasTransform() {
+			g.Concat(p.geoM(toPhysicalRect(p.frame, scale), scale))
+			ok = true
+		}
+	}
+	return g, ok
+}
+
+// toLocal converts a point in the coordinate space of the view's parent
+// to the untransformed coordinate space of the view.
+func (v *View) toLocal(x, y int) (int, int) {
+	if !v.hasTransform() {
+		return x, y
+	}
+	g := v.geoM(v.frame, 1)
+	if !g.IsInvertible() {
+		return math.MinInt32, math.MinInt32
+	}
+	g.Invert()
+	lx, ly := g.Apply(float64(x), float64(y))
+	return int(math.Floor(lx)), int(math.Floor(ly))
+}
+
+// toLocal converts a point for the child's view.
+func (c *child) toLocal(x, y int) (int, int) {
+	return c.item.toLocal(x, y)
+}
+
+// transformValue is a parsed CSS transform property.
+type transformValue struct {
+	translateX, translateY float64
+	scaleX, scaleY         float64
+	rotate                 float64
+}
+
+// then composes the transform function f after the transform, as CSS composes
+// the functions in the order they are written. f is a single function.
+// A rotation after a non-uniform scale would skew the view unless it turns by quarters.
+func (t *transformValue) then(f transformValue) error {
+	// The translation of f is moved before the scale and the rotation of t.
+	x, y := f.translateX*t.scaleX, f.translateY*t.scaleY
+	sin, cos := math.Sincos(t.rotate)
+	t.translateX += x*cos - y*sin
+	t.translateY += x*sin + y*cos
+	if f.rotate != 0 && t.scaleX != t.scaleY {
+		q := f.rotate / (math.Pi / 2)
+		if math.Abs(q-math.Round(q)) > 1e-9 {
+			return fmt.Errorf("rotate after a non-uniform scale is not supported")
+		}
+		if int(math.Round(q))%2 != 0 {
+			// A scale followed by a quarter turn is the turn followed by the scale of the swapped axes.
+			t.scaleX, t.scaleY = t.scaleY, t.scaleX
+		}
+	}
+	t.scaleX *= f.scaleX
+	t.scaleY *= f.scaleY
+	t.rotate += f.rotate
+	return nil
+}
+
+// parseTransform parses the CSS transform property such as
+// `translate(10px, 5px) scale(1.2) rotate(15deg)`.
+// The functions are composed in the order they are written.
+func parseTransform(val string) (any, error) {
+	t := transformValue{scaleX: 1, scaleY: 1}
+	if val == "none" {
+		return t, nil
+	}
+	for _, f := range splitValues(val, ' ') {
+		open := strings.Index(f, "(")
+		if open < 0 || !strings.HasSuffix(f, ")") {
+			return nil, fmt.Errorf("invalid transform: %s", f)
+		}
+		name := f[:open]
+		args := splitValues(f[open+1:len(f)-1], ',')
+		fn := transformValue{scaleX: 1, scaleY: 1}
+		if name == "rotate" {
+			if len(args) != 1 {
+				return nil, fmt.Errorf("invalid transform: %s", f)
+			}
+			deg, err := parseAngle(args[0])
+			if err != nil {
+				return nil, err
+			}
+			fn.rotate = deg * math.Pi / 180
+		} else {
+			nums := make([]float64, len(args))
+			for i, a := range args {
+				if strings.HasPrefix(name, "translate") {
+					a = strings.TrimSuffix(a, "px")
+				}
+				n, err := strconv.ParseFloat(a, 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid transform: %s", f)
+				}
+				nums[i] = n
+			}
+			switch {
+			case name == "translate" && len(nums) == 1:
+				fn.translateX = nums[0]
+			case name == "translate" && len(nums) == 2:
+				fn.translateX, fn.translateY = nums[0], nums[1]
+			case name == "translateX" && len(nums) == 1:
+				fn.translateX = nums[0]
+			case name == "translateY" && len(nums) == 1:
+				fn.translateY = nums[0]
+			case name == "scale" && len(nums) == 1:
+				fn.scaleX, fn.scaleY = nums[0], nums[0]
+			case name == "scale" && len(nums) == 2:
+				fn.scaleX, fn.scaleY = nums[0], nums[1]
+			case name == "scaleX" && len(nums) == 1:
+				fn.scaleX = nums[0]
+			case name == "scaleY" && len(nums) == 1:
+				fn.scaleY = nums[0]
+			default:
+				return nil, fmt.Errorf("invalid transform: %s", f)
+			}
+		}
+		if err := t.then(fn); err != nil {
+			return nil, fmt.Errorf("invalid transform: %s: %v", val, err)
+		}
+	}
+	return t, nil
+}
+
+// parseTransformOrigin parses the CSS transform-origin property such as
+// `50% 100%` or `left top`. It returns the origin as fractions of the frame.
+func parseTransformOrigin(val string) (any, error) {
+	parts := splitValues(val, ' ')
+	if len(parts) == 0 || len(parts) > 2 {
+		return nil, fmt.Errorf("invalid transform-origin: %s", val)
+	}
+	origin := [2]float64{0.5, 0.5}
+	for i, p := range parts {
+		axis := i
+		var f float64
+		switch p {
+		case "left":
+			f, axis = 0, 0
+		case "right":
+			f, axis = 1, 0
+		case "top":
+			f, axis = 0, 1
+		case "bottom":
+			f, axis = 1, 1
+		case "center":
+			f = 0.5
+		default:
+			n, err := parseFraction(p)
+			if err != nil {
+				return nil, fmt.Errorf("invalid transform-origin: %s", val)
+			}
+			f = n.(float64)
+		}
+		origin[axis] = f
+	}
+	return origin, nil
+}
+
+// setTransform sets the CSS transform of the view keeping its origin.
+func (v *View) setTransform(val transformValue) {
+	t := v.copyTransform()
+	t.TranslateX, t.TranslateY = val.translateX, val.translateY
+	t.SetScale(val.scaleX, val.scaleY)
+	t.Rotate = val.rotate
+	v.Transform = t
+}
+
+// setTransformOrigin sets the CSS transform-origin of the view.
+func (v *View) setTransformOrigin(origin [2]float64) {
+	t := v.copyTransform()
+	t.OriginX, t.OriginY = origin[0], origin[1]
+	v.Transform = t
+}
+
+// copyTransform returns a copy of the transform of the view so that
+// styles saved for media queries are not modified.
+func (v *View) copyTransform() *Transform {
+	t := NewTransform()
+	if v.Transform != nil {
+		*t = *v.Transform
+	}
+	return t
+}
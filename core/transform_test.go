@@ -0,0 +1,141 @@
+package core
+
+import (
+	"image"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransformHitTest(t *testing.T) {
+	h := &mockHandler{}
+	root := &View{Width: 200, Height: 200}
+	card := &View{Width: 200, Height: 200}
+	button := &View{Position: PositionAbsolute, Left: 0, Top: 0, Width: 20, Height: 20, Handler: h}
+	card.AddChild(button)
+	root.AddChild(card)
+	root.Update()
+
+	// Rotate the card half a turn around its center:
+	// the button is now drawn in the bottom-right corner.
+	card.SetTransform(&Transform{Rotate: math.Pi, OriginX: .5, OriginY: .5})
+
+	h.Init()
+	root.handleMouseButtonLeftPressed(10, 10)
+	require.False(t, h.IsPressed)
+
+	root.handleMouseButtonLeftPressed(190, 190)
+	require.True(t, h.IsPressed)
+	root.handleMouseButtonLeftReleased(190, 190)
+	require.True(t, h.IsReleased)
+	require.False(t, h.IsCancel)
+
+	// Scale it by 2 from the top-left corner and move it by 10px.
+	card.SetTransform(&Transform{TranslateX: 10, ScaleOffsetX: 1, ScaleOffsetY: 1})
+	h.Init()
+	root.handleMouseButtonLeftPressed(45, 35)
+	require.True(t, h.IsPressed)
+	root.handleMouseButtonLeftReleased(55, 35)
+	require.True(t, h.IsCancel)
+
+	// A press at the window origin is transformed like any other point.
+	card.SetTransform(&Transform{TranslateX: 190, TranslateY: 190})
+	h.Init()
+	root.handleMouseButtonLeftPressed(0, 0)
+	require.False(t, h.IsPressed)
+	card.SetTransform(&Transform{TranslateX: -10, TranslateY: -10})
+	root.handleMouseButtonLeftPressed(0, 0)
+	require.True(t, h.IsPressed)
+
+	// A transform that cannot be inverted receives no events.
+	card.SetTransform(&Transform{ScaleOffsetX: -1, ScaleOffsetY: -1})
+	h.Init()
+	root.handleMouseButtonLeftPressed(100, 100)
+	require.False(t, h.IsPressed)
+}
+
+func TestTransformZeroValue(t *testing.T) {
+	h := &mockHandler{}
+	root := &View{Width: 200, Height: 200}
+	card := &View{Width: 100, Height: 100, Transform: &Transform{}}
+	card.AddChild(&View{Position: PositionAbsolute, Width: 20, Height: 20, Handler: h})
+	root.AddChild(card)
+	root.Update()
+
+	// The zero value is the identity.
+	root.handleMouseButtonLeftPressed(10, 10)
+	require.True(t, h.IsPressed)
+	root.handleMouseButtonLeftReleased(10, 10)
+
+	// A literal rotation keeps the scale: half a turn around the top-left corner.
+	card.SetTransform(&Transform{Rotate: math.Pi})
+	h.Init()
+	root.handleMouseButtonLeftPressed(10, 10)
+	require.False(t, h.IsPressed)
+	card.SetTransform(&Transform{Rotate: math.Pi, TranslateX: 20, TranslateY: 20})
+	root.handleMouseButtonLeftPressed(10, 10)
+	require.True(t, h.IsPressed)
+}
+
+func TestTransformToLocal(t *testing.T) {
+	v := &View{}
+	v.frame = image.Rect(100, 100, 200, 200)
+	x, y := v.toLocal(10, 20)
+	require.Equal(t, [2]int{10, 20}, [2]int{x, y})
+
+	v.Transform = NewTransform()
+	v.Transform.SetScale(2, 2)
+	x, y = v.toLocal(50, 50)
+	require.Equal(t, [2]int{100, 100}, [2]int{x, y})
+}
+
+func TestParseTransform(t *testing.T) {
+	resetComponents()
+	v := Parse(`
+		<view style="width: 100; height: 100">
+			<view id="a" style="transform: translate(10px, -5px) scale(1.5) rotate(90deg); transform-origin: left bottom"></view>
+			<view id="b" style="transform-origin: 25% 0; transform: scaleX(2)"></view>
+			<view id="c" style="transform: none"></view>
+		</view>`, nil)
+
+	a := v.MustGetByID("a").Transform
+	require.Equal(t, 10., a.TranslateX)
+	require.Equal(t, -5., a.TranslateY)
+	sx, sy := a.Scale()
+	require.Equal(t, [2]float64{1.5, 1.5}, [2]float64{sx, sy})
+	require.InDelta(t, math.Pi/2, a.Rotate, 1e-9)
+	require.Equal(t, [2]float64{0, 1}, [2]float64{a.OriginX, a.OriginY})
+
+	b := v.MustGetByID("b").Transform
+	sx, sy = b.Scale()
+	require.Equal(t, [2]float64{2, 1}, [2]float64{sx, sy})
+	require.Equal(t, [2]float64{.25, 0}, [2]float64{b.OriginX, b.OriginY})
+
+	c := v.MustGetByID("c")
+	require.True(t, c.Transform.isIdentity())
+	require.False(t, c.hasEffect())
+
+	// The functions are composed in the order they are written.
+	for _, tt := range []struct {
+		val  string
+		want transformValue
+	}{
+		{"translate(10px) rotate(90deg)", transformValue{translateX: 10, scaleX: 1, scaleY: 1, rotate: math.Pi / 2}},
+		{"rotate(90deg) translate(10px)", transformValue{translateY: 10, scaleX: 1, scaleY: 1, rotate: math.Pi / 2}},
+		{"scale(2) translate(10px, 5px)", transformValue{translateX: 20, translateY: 10, scaleX: 2, scaleY: 2}},
+		{"scaleX(2) rotate(90deg)", transformValue{scaleX: 1, scaleY: 2, rotate: math.Pi / 2}},
+	} {
+		got, err := parseTransform(tt.val)
+		require.NoError(t, err, tt.val)
+		g := got.(transformValue)
+		require.InDelta(t, tt.want.translateX, g.translateX, 1e-9, tt.val)
+		require.InDelta(t, tt.want.translateY, g.translateY, 1e-9, tt.val)
+		require.Equal(t, [3]float64{tt.want.scaleX, tt.want.scaleY, tt.want.rotate}, [3]float64{g.scaleX, g.scaleY, g.rotate}, tt.val)
+	}
+
+	for _, val := range []string{"rotate(1, 2)", "skew(10deg)", "scale(abc)", "translate", "scaleX(2) rotate(45deg)"} {
+		_, err := parseTransform(val)
+		require.Error(t, err, val)
+	}
+}
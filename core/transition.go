@@ -0,0 +1,266 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Transition animates the changes of a property of a view instead of applying them at once,
+// like the transition property in CSS.
+type Transition struct {
+	Property Property
+	Duration time.Duration
+	Delay    time.Duration
+	// Easing is the easing of the transition. It is Linear if nil.
+	Easing Easing
+}
+
+// TransitionEndHandler represents a component that is notified
+// when a transition of its view has finished.
+type TransitionEndHandler interface {
+	HandleTransitionEnd(v *View, p Property)
+}
+
+// transitionState is the state of the transitions of a view.
+type transitionState struct {
+	// values are the values of the properties after the last update.
+	values  [propertyCount]float64
+	known   [propertyCount]bool
+	running [propertyCount]*Tween
+}
+
+// SetTransitions sets the transitions of the view.
+func (v *View) SetTransitions(transitions ...Transition) {
+	v.Transitions = transitions
+}
+
+// detectTransitions starts the transitions of the properties of the view tree
+// that have changed since the last update.
+func (v *View) detectTransitions() {
+	if len(v.Transitions) > 0 {
+		if v.transitions == nil {
+			v.transitions = &transitionState{}
+		}
+		for _, t := range v.Transitions {
+			v.startTransition(t)
+		}
+	}
+	for _, c := range v.children {
+		c.item.detectTransitions()
+	}
+}
+
+// startTransition animates the property of the transition from its value after
+// the last update if it has been changed since then. Unset properties are not animated.
+func (v *View) startTransition(t Transition) {
+	s, p := v.transitions, t.Property
+	if !p.isSet(v) {
+		if r := s.running[p]; r != nil {
+			r.stopped = true
+			s.running[p] = nil
+		}
+		s.known[p] = false
+		return
+	}
+	to := p.get(v)
+	if !s.known[p] || s.values[p] == to {
+		s.values[p], s.known[p] = to, true
+		return
+	}
+	if r := s.running[p]; r != nil {
+		r.stopped = true
+	}
+	from := s.values[p]
+	p.set(v, from)
+	tw := &Tween{
+		View:     v,
+		Property: p,
+		From:     &from,
+		To:       to,
+		Duration: t.Duration,
+		Delay:    t.Delay,
+		Easing:   t.Easing,
+	}
+	tw.OnComplete = func() {
+		s.running[p] = nil
+		if h, ok := v.Handler.(TransitionEndHandler); ok {
+			h.HandleTransitionEnd(v, p)
+		}
+	}
+	s.running[p] = tw
+	v.Animate(tw)
+}
+
+// recordTransitions saves the values of the transitioned properties of the view tree.
+func (v *View) recordTransitions() {
+	if s := v.transitions; s != nil {
+		for _, t := range v.Transitions {
+			s.values[t.Property] = t.Property.get(v)
+			s.known[t.Property] = t.Property.isSet(v)
+		}
+	}
+	for _, c := range v.children {
+		c.item.recordTransitions()
+	}
+}
+
+// transformProperties are the properties of the transform transition.
+var transformProperties = []Property{
+	PropertyTranslateX, PropertyTranslateY, PropertyScaleX, PropertyScaleY, PropertyRotate,
+}
+
+// parseTransition parses the CSS transition property, e.g. `width 300ms ease-in 100ms, opacity 1s`.
+func parseTransition(val string) (any, error) {
+	if val == "none" {
+		return []Transition(nil), nil
+	}
+	var transitions []Transition
+	for _, part := range splitValues(val, ',') {
+		var props []Property
+		var t Transition
+		times := 0
+		for _, f := range splitValues(part, ' ') {
+			if d, err := parseDuration(f); err == nil {
+				if times == 0 {
+					t.Duration = d
+				} else {
+					t.Delay = d
+				}
+				times++
+				continue
+			}
+			if e, err := parseEasing(f); err == nil {
+				t.Easing = e
+				continue
+			}
+			if props != nil {
+				return nil, fmt.Errorf("invalid transition: %s", part)
+			}
+			var ok bool
+			if props, ok = transitionProperties(f); !ok {
+				return nil, fmt.Errorf("invalid transition property: %s", f)
+			}
+		}
+		if times > 2 {
+			return nil, fmt.Errorf("invalid transition: %s", part)
+		}
+		if props == nil {
+			props, _ = transitionProperties("all")
+		}
+		for _, p := range props {
+			t.Property = p
+			transitions = append(transitions, t)
+		}
+	}
+	return transitions, nil
+}
+
+// transitionProperties returns the properties of a CSS property name of a transition.
+func transitionProperties(name string) ([]Property, bool) {
+	switch name {
+	case "all":
+		props := make([]Property, propertyCount)
+		for i := range props {
+			props[i] = Property(i)
+		}
+		return props, true
+	case "transform":
+		return transformProperties, true
+	case "margin":
+		return []Property{PropertyMarginLeft, PropertyMarginTop, PropertyMarginRight, PropertyMarginBottom}, true
+	}
+	for i, n := range propertyNames {
+		if n == name {
+			return []Property{Property(i)}, true
+		}
+	}
+	return nil, false
+}
+
+// parseDuration parses a CSS time such as `300ms` or `0.3s`.
+func parseDuration(val string) (time.Duration, error) {
+	unit := time.Second
+	num := strings.TrimSuffix(val, "s")
+	if num == val {
+		return 0, fmt.Errorf("invalid time: %s", val)
+	}
+	if strings.HasSuffix(num, "m") {
+		unit = time.Millisecond
+		num = strings.TrimSuffix(num, "m")
+	}
+	f, err := strconv.ParseFloat(num, 64)
+	if err != nil || f < 0 {
+		return 0, fmt.Errorf("invalid time: %s", val)
+	}
+	return time.Duration(f * float64(unit)), nil
+}
+
+var cssEasings = map[string]Easing{
+	"linear":      Linear,
+	"ease":        CubicBezier(0.25, 0.1, 0.25, 1),
+	"ease-in":     CubicBezier(0.42, 0, 1, 1),
+	"ease-out":    CubicBezier(0, 0, 0.58, 1),
+	"ease-in-out": CubicBezier(0.42, 0, 0.58, 1),
+}
+
+// parseEasing parses a CSS easing function: a keyword or cubic-bezier().
+func parseEasing(val string) (Easing, error) {
+	if e, ok := cssEasings[val]; ok {
+		return e, nil
+	}
+	if !strings.HasPrefix(val, "cubic-bezier(") || !strings.HasSuffix(val, ")") {
+		return nil, fmt.Errorf("invalid easing: %s", val)
+	}
+	args := splitValues(val[len("cubic-bezier("):len(val)-1], ',')
+	if len(args) != 4 {
+		return nil, fmt.Errorf("invalid easing: %s", val)
+	}
+	var p [4]float64
+	for i, a := range args {
+		f, err := strconv.ParseFloat(a, 64)
+		if err != nil || (i%2 == 0 && (f < 0 || f > 1)) {
+			return nil, fmt.Errorf("invalid easing: %s", val)
+		}
+		p[i] = f
+	}
+	return CubicBezier(p[0], p[1], p[2], p[3]), nil
+}
+
+// SetStyle applies CSS declarations to the view like its style attribute.
+// The changes of the properties with a transition are animated.
+func (v *View) SetStyle(style string) {
+	parseStyle(v, style)
+	if v.css != nil {
+		v.css.style = mergeStyle(v.css.style, style)
+	}
+	v.Layout()
+}
+
+// mergeStyle returns the declarations of base followed by those of style.
+// A property keeps only its last declaration, so that it is applied in the same order.
+func mergeStyle(base, style string) string {
+	var props, vals []string
+	for _, pair := range strings.Split(base+";"+style, ";") {
+		kv := strings.SplitN(pair, ":", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		k := strings.TrimSpace(kv[0])
+		for i, p := range props {
+			if p == k {
+				props = append(props[:i], props[i+1:]...)
+				vals = append(vals[:i], vals[i+1:]...)
+				break
+			}
+		}
+		props = append(props, k)
+		vals = append(vals, strings.TrimSpace(kv[1]))
+	}
+	decls := make([]string, len(props))
+	for i := range props {
+		decls[i] = props[i] + ": " + vals[i]
+	}
+	return strings.Join(decls, "; ")
+}
@@ -0,0 +1,150 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type transitionEndHandler struct {
+	ended []Property
+}
+
+func (h *transitionEndHandler) HandleTransitionEnd(v *View, p Property) {
+	h.ended = append(h.ended, p)
+}
+
+func TestParseTransition(t *testing.T) {
+	resetComponents()
+	v := Parse(`
+		<view style="width: 100; height: 100">
+			<view id="a" style="transition: width 1s linear, opacity 500ms ease-in 100ms"></view>
+			<view id="b" style="transition: transform 0.2s cubic-bezier(0.1, 0.7, 1.0, 0.1)"></view>
+		</view>`, nil)
+
+	a := v.MustGetByID("a").Transitions
+	require.Len(t, a, 2)
+	require.Equal(t, PropertyWidth, a[0].Property)
+	require.Equal(t, time.Second, a[0].Duration)
+	require.Equal(t, 0.5, a[0].Easing(0.5))
+	require.Equal(t, PropertyOpacity, a[1].Property)
+	require.Equal(t, 500*time.Millisecond, a[1].Duration)
+	require.Equal(t, 100*time.Millisecond, a[1].Delay)
+
+	b := v.MustGetByID("b").Transitions
+	require.Len(t, b, len(transformProperties))
+	require.Equal(t, PropertyTranslateX, b[0].Property)
+	require.Equal(t, 200*time.Millisecond, b[4].Duration)
+
+	all, err := parseTransition("1s")
+	require.NoError(t, err)
+	require.Len(t, all, int(propertyCount))
+
+	for _, val := range []string{"width 1x", "color 1s", "width 1s 1s 1s", "width height 1s", "width 1s cubic-bezier(2, 0, 1, 1)"} {
+		_, err := parseTransition(val)
+		require.Error(t, err, val)
+	}
+}
+
+func TestTransition(t *testing.T) {
+	advance := fakeClock(t)
+	h := &transitionEndHandler{}
+	root := &View{Width: 200, Height: 100}
+	box := &View{Width: 20, Height: 10, Handler: h}
+	box.SetTransitions(Transition{Property: PropertyWidth, Duration: time.Second})
+	root.AddChild(box)
+	root.Update()
+
+	box.SetWidth(120)
+	root.Update()
+	require.Equal(t, 20, box.frame.Dx(), "the change does not snap")
+	advance(500 * time.Millisecond)
+	root.Update()
+	require.Equal(t, 70, box.frame.Dx())
+
+	// A change during the transition starts from the current value.
+	box.SetWidth(20)
+	root.Update()
+	require.Equal(t, 70, box.frame.Dx())
+	advance(500 * time.Millisecond)
+	root.Update()
+	require.Equal(t, 45, box.frame.Dx())
+	require.Empty(t, h.ended)
+
+	advance(time.Second)
+	root.Update()
+	require.Equal(t, 20, box.frame.Dx())
+	require.Equal(t, []Property{PropertyWidth}, h.ended)
+	require.False(t, box.IsAnimating())
+}
+
+func TestSetStyleTransition(t *testing.T) {
+	advance := fakeClock(t)
+	resetComponents()
+	root := Parse(`
+		<view style="width: 100; height: 100">
+			<view id="a" style="width: 10; height: 10; transition: opacity 1s, transform 1s"></view>
+		</view>`, nil)
+	root.Update()
+
+	a := root.MustGetByID("a")
+	a.SetStyle("opacity: 0; transform: translateX(40px)")
+	root.Update()
+	require.Equal(t, 1., a.opacity())
+	require.Equal(t, 0., a.Transform.TranslateX)
+	advance(250 * time.Millisecond)
+	root.Update()
+	require.InDelta(t, 0.75, a.opacity(), 1e-9)
+	require.InDelta(t, 10, a.Transform.TranslateX, 1e-9)
+	advance(time.Second)
+	root.Update()
+	require.Equal(t, 0., a.opacity())
+	require.Equal(t, 40., a.Transform.TranslateX)
+}
+
+func TestSetStyleMerges(t *testing.T) {
+	resetComponents()
+	root := Parse(`
+		<view style="width: 100; height: 100">
+			<view id="a" style="width: 10; height: 10; opacity: 1"></view>
+		</view>`, nil)
+	a := root.MustGetByID("a")
+	for i := 0; i < 100; i++ {
+		a.SetStyle(fmt.Sprintf("opacity: %d%%; left: %d", i, i))
+	}
+	require.Equal(t, "opacity: 99%; left: 99", a.css.style)
+
+	a.SetStyle("width: 20")
+	require.Equal(t, "opacity: 99%; left: 99; width: 20", a.css.style)
+}
+
+func TestTransitionAllUnsetProperties(t *testing.T) {
+	advance := fakeClock(t)
+	resetComponents()
+	root := Parse(`
+		<view style="width: 200; height: 100">
+			<view id="a" style="position: absolute; left: 10; width: 10; height: 10; transition: all 1s"></view>
+		</view>`, nil)
+	root.Update()
+
+	a := root.MustGetByID("a")
+	a.SetWidth(50)
+	root.Update()
+	advance(500 * time.Millisecond)
+	root.Update()
+	require.Equal(t, 30, a.frame.Dx())
+	require.Nil(t, a.Right, "unset right is not animated")
+	require.Nil(t, a.Bottom, "unset bottom is not animated")
+
+	// Setting an unset property snaps, and its later changes are animated.
+	a.SetRight(20)
+	root.Update()
+	require.Equal(t, 20, *a.Right)
+	a.SetRight(40)
+	root.Update()
+	advance(500 * time.Millisecond)
+	root.Update()
+	require.Equal(t, 30, *a.Right)
+}
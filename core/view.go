@@ -1,12 +1,12 @@
-package furex
+package core
 
 import (
 	"fmt"
 	"image"
+	"image/color"
 	"strings"
 	"sync"
-
-	"github.com/hajimehoshi/ebiten/v2"
+	"time"
 )
 
 // View represents a UI element.
@@ -36,6 +36,73 @@ type View struct {
 	Shrink       float64
 	Display      Display
 
+	// Anchors, offsets and pivot are used when the parent is a DisplayAnchor container.
+	AnchorLeft   float64
+	AnchorTop    float64
+	AnchorRight  float64
+	AnchorBottom float64
+	OffsetLeft   int
+	OffsetTop    int
+	OffsetRight  int
+	OffsetBottom int
+	PivotX       float64
+	PivotY       float64
+
+	// Box properties paint the background and the border of the view.
+	// The box is painted when the view has no handler or when the handler
+	// implements BoxPaintHandler.
+	BackgroundColor color.Color
+	BorderWidth     int
+	BorderColor     color.Color
+	BorderRadius    int
+	// BackgroundImage is the name of a registered image drawn over the background color.
+	// BackgroundSlice makes it a nine-slice image: the insets, in image pixels,
+	// are the corners that are never stretched.
+	BackgroundImage string
+	BackgroundSlice Insets
+	BackgroundSize  BackgroundSize
+	// BackgroundGradient is drawn over the background color and under the background image.
+	BackgroundGradient *Gradient
+	// BoxShadows are painted behind the box, the first one on top.
+	BoxShadows []BoxShadow
+
+	// Opacity is the opacity of the view and its descendants from 0 to 1. It is 1 if nil.
+	Opacity *float64
+	// ColorM is the color matrix applied to the view and its descendants,
+	// e.g. to tint a disabled section grey.
+	ColorM *ColorM
+	// Transform translates, scales and rotates the view and its descendants
+	// when they are drawn. Pointer events are transformed accordingly.
+	Transform *Transform
+	// Transitions animate the changes of the properties of the view.
+	// The changes are detected on every update of the root view.
+	Transitions []Transition
+	// KeyframeAnimations play registered keyframes on the view.
+	// The added animations start on the next update of the root view.
+	KeyframeAnimations []KeyframeAnimation
+	// AnimateLayout animates the children of the view when the layout changes,
+	// and when children are added or removed.
+	AnimateLayout *LayoutAnimation
+	// Cache renders the view and its descendants into an offscreen image
+	// that is reused until the view is invalidated by a layout, a child change
+	// or a call to Invalidate. The drawing is clipped to the frame of the view.
+	Cache bool
+
+	// FontFamily, FontSize and Color are the font and the color of the text.
+	// They are inherited from the ancestors when they are not set.
+	// The font family is a comma separated list of registered families.
+	FontFamily string
+	FontSize   int
+	Color      color.Color
+	TextAlign  TextAlign
+	// LineHeight is the height of a line as a multiple of the font size. It is 1.2 if 0.
+	LineHeight   float64
+	WhiteSpace   WhiteSpace
+	TextOverflow TextOverflow
+	// Spans is the rich text of the view. When it is not empty,
+	// it is drawn instead of Text.
+	Spans []TextSpan
+
 	ID      string
 	Raw     string
 	TagName string
@@ -45,15 +112,58 @@ type View struct {
 
 	Handler Handler
 
+	// LayoutEngine lays out the children of the view.
+	// If it is nil, FlexLayout is used, or AnchorLayout when Display is DisplayAnchor.
+	LayoutEngine LayoutEngine
+
 	containerEmbed
 	flexEmbed
 	lock      sync.Mutex
 	hasParent bool
 	parent    *View
+	css       *cssState
+	// keyframes are the @keyframes of the document parsed into the view.
+	keyframes map[string]*Keyframes
+	uiScale   float64
+	renderer  ScreenRenderer
+
+	cache      Image
+	cacheOwner CacheRenderer
+	cacheValid bool
+
+	lastDraw  time.Time
+	deltaTime time.Duration
+
+	animations        []Animation
+	animationsStopped bool
+	lastUpdate        time.Time
+	transitions       *transitionState
+	keyframePlayers   []*keyframePlayer
+	layoutFlip        *layoutFlip
+	layoutFrames      map[*View]image.Rectangle
+	exiting           []*child
+
+	listeners []*eventListener
+	keysDown  [keyCount]bool
+
+	textLayout    *textLayout
+	textLayoutKey textLayoutKey
 }
 
 // Update updates the view
 func (v *View) Update() {
+	v.update(true)
+}
+
+// update lays out and updates the view tree. The root view processes
+// the input events only if events is true.
+func (v *View) update(events bool) {
+	if !v.hasParent {
+		v.detectTransitions()
+		v.syncKeyframeAnimations()
+		v.stepAnimations()
+		v.recordTransitions()
+	}
 	if v.isDirty {
 		v.startLayout()
 	}
@@ -62,8 +172,8 @@ func (v *View) Update() {
 		v.item.Update()
 		v.item.processHandler()
 	}
-	if !v.hasParent {
-		v.processEvent()
+	if events && !v.hasParent {
+		v.processEvent(v.UIScale(), v.toLocal)
 	}
 }
 
@@ -84,49 +194,110 @@ func (v *View) startLayout() {
 	if !v.hasParent {
 		v.frame = image.Rect(v.Left, v.Top, v.Left+v.Width, v.Top+v.Height)
 	}
-	v.flexEmbed.View = v
-
+	before, animate := v.beginLayoutAnimation()
 	for _, child := range v.children {
 		if child.item.Position == PositionStatic {
 			child.item.startLayout()
 		}
 	}
 
-	v.layout(v.frame.Dx(), v.frame.Dy(), &v.containerEmbed)
+	items := make([]*LayoutItem, 0, len(v.children))
+	for _, c := range v.children {
+		if c.item.Display == DisplayNone {
+			continue
+		}
+		if c.item.Position == PositionAbsolute {
+			layoutAbsolute(c, &v.containerEmbed)
+			continue
+		}
+		items = append(items, &LayoutItem{View: c.item, child: c})
+	}
+	v.layoutEngine().Layout(v, v.frame.Dx(), v.frame.Dy(), items)
+	if v.paintsText() {
+		v.sizeToText()
+	}
+	if animate {
+		v.endLayoutAnimation(before)
+	}
 	v.isDirty = false
 }
 
-// UpdateWithSize the view with modified height and width
+// UpdateWithSize the view with modified height and width.
+// The size is in screen pixels and is divided by the UI scale of the view.
+// The CSS @media rules of a parsed view are re-evaluated against the new size.
 func (v *View) UpdateWithSize(width, height int) {
+	v.setSize(width, height)
+	v.Update()
+}
+
+func (v *View) setSize(width, height int) {
+	width, height = toLogicalPoint(width, height, v.UIScale())
 	if !v.hasParent && (v.Width != width || v.Height != height) {
 		v.Height = height
 		v.Width = width
+		v.applyMediaQueries(width, height)
 		v.isDirty = true
 	}
-	v.Update()
 }
 
 // Layout marks the view as dirty
 func (v *View) Layout() {
 	v.isDirty = true
+	v.textLayout = nil
+	v.Invalidate()
 	if v.hasParent {
 		v.parent.isDirty = true
 	}
 }
 
 // Draw draws the view
-func (v *View) Draw(screen *ebiten.Image) {
+func (v *View) Draw(screen Image) {
+	if v.renderer == nil {
+		v.renderer = newScreenRenderer()
+	}
+	v.renderer.Reset(screen)
+	v.Render(v.renderer)
+}
+
+// Render draws the view with the renderer.
+func (v *View) Render(r Renderer) {
 	if v.isDirty {
 		v.startLayout()
 	}
 	if !v.hasParent {
-		v.handleDrawRoot(screen, v.frame)
+		v.updateDeltaTime()
+		effect := v.beginEffect(r)
+		frame := toPhysicalRect(v.frame, v.UIScale())
+		v.drawCached(r, v.boxShadowBounds(frame), func(r Renderer) {
+			if v.shouldPaintBox() {
+				v.paintBox(r, frame)
+			}
+			if v.shouldPaintText() {
+				v.paintText(r, frame)
+			}
+			if v.Handler != nil {
+				v.drawHandler(v.newDrawContext(r, frame, nil))
+			}
+			v.drawChildren(r)
+		})
+		v.endEffect(r, effect)
+	} else {
+		v.drawChildren(r)
 	}
-	if !v.Hidden && v.Display != DisplayNone {
-		v.containerEmbed.Draw(screen)
+	if isDebug() && r.Target() != nil && !v.hasParent && v.Display != DisplayNone {
+		debugBorders(r, v.containerEmbed, v.UIScale())
 	}
-	if Debug && !v.hasParent && v.Display != DisplayNone {
-		debugBorders(screen, v.containerEmbed)
+	if !v.hasParent {
+		flush(r)
+	}
+}
+
+func (v *View) drawChildren(r Renderer) {
+	if !v.Hidden && v.Display != DisplayNone {
+		for _, c := range v.exiting {
+			v.drawChild(r, c)
+		}
+		v.containerEmbed.draw(r)
 	}
 }
 
@@ -153,8 +324,8 @@ func (v *View) RemoveChild(cv *View) bool {
 		if child.item == cv {
 			v.children = append(v.children[:i], v.children[i+1:]...)
 			v.isDirty = true
-			cv.hasParent = false
-			cv.parent = nil
+			v.Invalidate()
+			v.detachChild(child)
 			return true
 		}
 	}
@@ -164,11 +335,12 @@ func (v *View) RemoveChild(cv *View) bool {
 // RemoveAll removes all children view
 func (v *View) RemoveAll() {
 	v.isDirty = true
-	for _, child := range v.children {
-		child.item.hasParent = false
-		child.item.parent = nil
-	}
+	v.Invalidate()
+	children := v.children
 	v.children = []*child{}
+	for _, child := range children {
+		v.detachChild(child)
+	}
 }
 
 // PopChild remove the last child view add to this view
@@ -179,15 +351,23 @@ func (v *View) PopChild() *View {
 	c := v.children[len(v.children)-1]
 	v.children = v.children[:len(v.children)-1]
 	v.isDirty = true
-	c.item.hasParent = false
-	c.item.parent = nil
+	v.Invalidate()
+	v.detachChild(c)
 	return c.item
 }
 
 func (v *View) addChild(cv *View) *View {
 	child := &child{item: cv, handledTouchID: -1}
+	for i, c := range v.exiting {
+		if c.item == cv {
+			v.exiting = append(v.exiting[:i], v.exiting[i+1:]...)
+			c.cancelExit()
+			break
+		}
+	}
 	v.children = append(v.children, child)
 	v.isDirty = true
+	v.Invalidate()
 	cv.hasParent = true
 	cv.parent = v
 	return v
@@ -364,6 +544,25 @@ func (v *View) SetDisplay(display Display) {
 	v.Layout()
 }
 
+// SetLayoutEngine sets the layout engine of the view.
+func (v *View) SetLayoutEngine(engine LayoutEngine) {
+	v.LayoutEngine = engine
+	v.Layout()
+}
+
+// SetOpacity sets the opacity of the view and its descendants.
+func (v *View) SetOpacity(opacity float64) {
+	v.Opacity = Float(opacity)
+	v.invalidateParent()
+}
+
+// SetColorM sets the color matrix of the view and its descendants.
+// Passing nil removes the color matrix.
+func (v *View) SetColorM(colorM *ColorM) {
+	v.ColorM = colorM
+	v.invalidateParent()
+}
+
 // SetHidden sets the hidden property of the view.
 func (v *View) SetHidden(hidden bool) {
 	v.Hidden = hidden
@@ -400,14 +599,12 @@ func (v *View) Config() ViewConfig {
 	return cfg
 }
 
-func (v *View) handleDrawRoot(screen *ebiten.Image, b image.Rectangle) {
-	if h, ok := v.Handler.(DrawHandler); ok {
-		h.HandleDraw(screen, b)
-		return
-	}
-	if h, ok := v.Handler.(Drawer); ok {
-		h.Draw(screen, b, v)
+// setFrame sets the frame of the view and invalidates its cache if the frame changes.
+func (v *View) setFrame(frame image.Rectangle) {
+	if v.frame != frame {
+		v.Invalidate()
 	}
+	v.containerEmbed.setFrame(frame)
 }
 
 // This is for debugging and testing.
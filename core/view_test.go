@@ -1,4 +1,4 @@
-package furex
+package core
 
 import (
 	"image"
@@ -1,54 +1,420 @@
+// Package furex is the Ebitengine adapter of the furex UI library.
+// The view tree, the layout, the CSS and the event routing are in the engine-neutral
+// core package; this package draws the views on ebiten images, reads the input of
+// ebiten and lets handlers use ebiten types. The types and functions of core are
+// re-exported here so that games only import furex.
 package furex
 
 import (
-	"image/color"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/yohamta/furex/v2/internal/graphic"
+	"github.com/yohamta/furex/v2/core"
 )
 
-var (
-	Debug           = false
-	debugColor      = color.RGBA{0xff, 0, 0, 0xff}
-	debugColorShift = ebiten.ColorM{}
-)
-
-func debugBorders(screen *ebiten.Image, root containerEmbed) {
-	queue := []containerEmbed{}
-	queue = append(queue, root)
-	renderColor := resetDebugColor()
-
-	for len(queue) > 0 {
-		levelSize := len(queue)
-		for levelSize != 0 {
-			curr := queue[0]
-			queue = queue[1:]
-
-			graphic.DrawRect(screen, &graphic.DrawRectOpts{
-				Rect:        curr.frame,
-				Color:       renderColor,
-				StrokeWidth: 2,
-			})
-
-			for _, c := range curr.children {
-				if c.item.Display == DisplayNone {
-					continue
-				}
-				queue = append(queue, c.item.containerEmbed)
-			}
-			levelSize--
-		}
-
-		renderColor = rotateDebugColor()
-	}
-}
+// Debug draws the borders and the frames of the views when they are drawn.
+var Debug = false
 
-func rotateDebugColor() color.Color {
-	debugColorShift.RotateHue(1.66)
-	return debugColorShift.Apply(debugColor)
+func init() {
+	core.SetScreenRenderer(func() core.ScreenRenderer { return newEbitenRenderer(nil) })
+	core.SetInput(ebitenInput{})
+	core.SetHandlerAdapter(handlerAdapter{})
+	core.SetDebugFlag(&Debug)
 }
 
-func resetDebugColor() color.Color {
-	debugColorShift = ebiten.ColorM{}
-	return debugColor
+// View tree and layout.
+type (
+	View         = core.View
+	ViewConfig   = core.ViewConfig
+	Handler      = core.Handler
+	LayoutEngine = core.LayoutEngine
+	LayoutItem   = core.LayoutItem
+	FlexLayout   = core.FlexLayout
+	AnchorLayout = core.AnchorLayout
+	AnchorPreset = core.AnchorPreset
+	Direction    = core.Direction
+	Justify      = core.Justify
+	AlignItem    = core.AlignItem
+	AlignContent = core.AlignContent
+	FlexAlign    = core.FlexAlign
+	FlexWrap     = core.FlexWrap
+	Position     = core.Position
+	Display      = core.Display
+	Transform    = core.Transform
+	Insets       = core.Insets
+)
+
+const (
+	Row                      = core.Row
+	Column                   = core.Column
+	JustifyStart             = core.JustifyStart
+	JustifyEnd               = core.JustifyEnd
+	JustifyCenter            = core.JustifyCenter
+	JustifySpaceBetween      = core.JustifySpaceBetween
+	JustifySpaceAround       = core.JustifySpaceAround
+	AlignItemStart           = core.AlignItemStart
+	AlignItemEnd             = core.AlignItemEnd
+	AlignItemCenter          = core.AlignItemCenter
+	AlignItemStretch         = core.AlignItemStretch
+	AlignContentStart        = core.AlignContentStart
+	AlignContentEnd          = core.AlignContentEnd
+	AlignContentCenter       = core.AlignContentCenter
+	AlignContentSpaceBetween = core.AlignContentSpaceBetween
+	AlignContentSpaceAround  = core.AlignContentSpaceAround
+	AlignContentStretch      = core.AlignContentStretch
+	FlexStart                = core.FlexStart
+	FlexEnd                  = core.FlexEnd
+	FlexCenter               = core.FlexCenter
+	FlexSpaceBetween         = core.FlexSpaceBetween
+	NoWrap                   = core.NoWrap
+	Wrap                     = core.Wrap
+	WrapReverse              = core.WrapReverse
+	PositionStatic           = core.PositionStatic
+	PositionAbsolute         = core.PositionAbsolute
+	DisplayFlex              = core.DisplayFlex
+	DisplayNone              = core.DisplayNone
+	DisplayAnchor            = core.DisplayAnchor
+	AnchorPresetTopLeft      = core.AnchorPresetTopLeft
+	AnchorPresetTop          = core.AnchorPresetTop
+	AnchorPresetTopRight     = core.AnchorPresetTopRight
+	AnchorPresetLeft         = core.AnchorPresetLeft
+	AnchorPresetCenter       = core.AnchorPresetCenter
+	AnchorPresetRight        = core.AnchorPresetRight
+	AnchorPresetBottomLeft   = core.AnchorPresetBottomLeft
+	AnchorPresetBottom       = core.AnchorPresetBottom
+	AnchorPresetBottomRight  = core.AnchorPresetBottomRight
+	AnchorPresetFull         = core.AnchorPresetFull
+)
+
+// NewTransform returns the identity transform centered on the view.
+func NewTransform() *Transform { return core.NewTransform() }
+
+// Handlers. Drawer, DrawHandler, ButtonHandler, TouchHandler and MouseButtonHandler
+// use ebiten types and are declared in handler.go.
+type (
+	Updater                = core.Updater
+	UpdateHandler          = core.UpdateHandler
+	ContextDrawer          = core.ContextDrawer
+	DrawContext            = core.DrawContext
+	NotButton              = core.NotButton
+	BoxPaintHandler        = core.BoxPaintHandler
+	TextPaintHandler       = core.TextPaintHandler
+	MouseHandler           = core.MouseHandler
+	MouseLeftButtonHandler = core.MouseLeftButtonHandler
+	MouseEnterLeaveHandler = core.MouseEnterLeaveHandler
+	WheelHandler           = core.WheelHandler
+	SwipeHandler           = core.SwipeHandler
+	SwipeDirection         = core.SwipeDirection
+)
+
+const (
+	SwipeDirectionLeft  = core.SwipeDirectionLeft
+	SwipeDirectionRight = core.SwipeDirectionRight
+	SwipeDirectionUp    = core.SwipeDirectionUp
+	SwipeDirectionDown  = core.SwipeDirectionDown
+)
+
+// Events.
+type (
+	Event         = core.Event
+	EventType     = core.EventType
+	EventPhase    = core.EventPhase
+	EventListener = core.EventListener
+	EventHandler  = core.EventHandler
+	MouseButton   = core.MouseButton
+)
+
+const (
+	EventPointerDown    = core.EventPointerDown
+	EventPointerUp      = core.EventPointerUp
+	EventPointerMove    = core.EventPointerMove
+	EventKeyDown        = core.EventKeyDown
+	EventKeyUp          = core.EventKeyUp
+	EventWheel          = core.EventWheel
+	EventPhaseNone      = core.EventPhaseNone
+	EventPhaseCapturing = core.EventPhaseCapturing
+	EventPhaseAtTarget  = core.EventPhaseAtTarget
+	EventPhaseBubbling  = core.EventPhaseBubbling
+	MouseButtonLeft     = core.MouseButtonLeft
+	MouseButtonRight    = core.MouseButtonRight
+	MouseButtonMiddle   = core.MouseButtonMiddle
+)
+
+// HTML and CSS.
+type (
+	ParseOptions  = core.ParseOptions
+	Component     = core.Component
+	ComponentsMap = core.ComponentsMap
+	ErrorList     = core.ErrorList
+)
+
+// Parse parses HTML and CSS into a view tree.
+func Parse(html string, opts *ParseOptions) *View { return core.Parse(html, opts) }
+
+// RegisterComponents registers components that can be used in the HTML of any Parse call.
+func RegisterComponents(cs ComponentsMap) { core.RegisterComponents(cs) }
+
+// Int returns a pointer to the int.
+func Int(i int) *int { return core.Int(i) }
+
+// Float returns a pointer to the float64.
+func Float(f float64) *float64 { return core.Float(f) }
+
+// Painting.
+type (
+	BoxShadow      = core.BoxShadow
+	Gradient       = core.Gradient
+	GradientKind   = core.GradientKind
+	ColorStop      = core.ColorStop
+	BackgroundSize = core.BackgroundSize
+	ColorM         = core.ColorM
+	GeoM           = core.GeoM
+	Image          = core.Image
+)
+
+const (
+	GradientLinear        = core.GradientLinear
+	GradientRadial        = core.GradientRadial
+	BackgroundSizeStretch = core.BackgroundSizeStretch
+	BackgroundSizeTile    = core.BackgroundSizeTile
+	BackgroundSizeContain = core.BackgroundSizeContain
+	BackgroundSizeCover   = core.BackgroundSizeCover
+)
+
+// RegisterImage registers an image with a name so that views can use it
+// as `background-image` in CSS or BackgroundImage in Go.
+func RegisterImage(name string, img Image) { core.RegisterImage(name, img) }
+
+// RegisterAtlas registers the sub images of an atlas image.
+// The key of rects is the name of each sub image.
+var RegisterAtlas = core.RegisterAtlas
+
+// LoadImages decodes the PNG or JPEG files at the given paths of fsys
+// and registers them. Each image is named after its file name without the extension,
+// e.g. `images/panel.png` is registered as `panel`.
+var LoadImages = core.LoadImages
+
+// GetImage returns the image registered with the name as an ebiten image.
+func GetImage(name string) (*ebiten.Image, bool) {
+	img, ok := core.GetImage(name)
+	if !ok {
+		return nil, false
+	}
+	return toEbitenImage(img), true
 }
+
+// Text.
+type (
+	TextAlign    = core.TextAlign
+	TextOverflow = core.TextOverflow
+	WhiteSpace   = core.WhiteSpace
+	TextSpan     = core.TextSpan
+	FontVariant  = core.FontVariant
+)
+
+const (
+	TextAlignLeft        = core.TextAlignLeft
+	TextAlignCenter      = core.TextAlignCenter
+	TextAlignRight       = core.TextAlignRight
+	TextOverflowClip     = core.TextOverflowClip
+	TextOverflowEllipsis = core.TextOverflowEllipsis
+	WhiteSpaceNormal     = core.WhiteSpaceNormal
+	WhiteSpaceNoWrap     = core.WhiteSpaceNoWrap
+	FontRegular          = core.FontRegular
+	FontBold             = core.FontBold
+	FontItalic           = core.FontItalic
+	FontBoldItalic       = core.FontBoldItalic
+	DefaultFontFamily    = core.DefaultFontFamily
+	DefaultFontSize      = core.DefaultFontSize
+)
+
+var (
+	// RegisterFont registers a TrueType or OpenType font with a family name.
+	RegisterFont = core.RegisterFont
+	// RegisterFontVariant registers the bold, italic or bold italic variant of a font family.
+	RegisterFontVariant = core.RegisterFontVariant
+	// RegisterFontFace registers a font face with a family name for every size.
+	RegisterFontFace = core.RegisterFontFace
+	// GetFontFace returns the face of the first registered family in the comma-separated list.
+	GetFontFace = core.GetFontFace
+)
+
+// Animation.
+type (
+	Animation = core.Animation
+	Tween     = core.Tween
+	Group     = core.Group
+	Property  = core.Property
+	Easing    = core.Easing
+
+	Transition           = core.Transition
+	TransitionEndHandler = core.TransitionEndHandler
+
+	Keyframes           = core.Keyframes
+	Keyframe            = core.Keyframe
+	KeyframeAnimation   = core.KeyframeAnimation
+	AnimationDirection  = core.AnimationDirection
+	AnimationFillMode   = core.AnimationFillMode
+	AnimationEndHandler = core.AnimationEndHandler
+	AnimationState      = core.AnimationState
+
+	LayoutAnimation = core.LayoutAnimation
+)
+
+const (
+	PropertyLeft         = core.PropertyLeft
+	PropertyTop          = core.PropertyTop
+	PropertyRight        = core.PropertyRight
+	PropertyBottom       = core.PropertyBottom
+	PropertyWidth        = core.PropertyWidth
+	PropertyHeight       = core.PropertyHeight
+	PropertyMarginLeft   = core.PropertyMarginLeft
+	PropertyMarginTop    = core.PropertyMarginTop
+	PropertyMarginRight  = core.PropertyMarginRight
+	PropertyMarginBottom = core.PropertyMarginBottom
+	PropertyOpacity      = core.PropertyOpacity
+	PropertyTranslateX   = core.PropertyTranslateX
+	PropertyTranslateY   = core.PropertyTranslateY
+	PropertyScaleX       = core.PropertyScaleX
+	PropertyScaleY       = core.PropertyScaleY
+	PropertyRotate       = core.PropertyRotate
+)
+
+const (
+	AnimationDirectionNormal           = core.AnimationDirectionNormal
+	AnimationDirectionReverse          = core.AnimationDirectionReverse
+	AnimationDirectionAlternate        = core.AnimationDirectionAlternate
+	AnimationDirectionAlternateReverse = core.AnimationDirectionAlternateReverse
+	AnimationFillModeNone              = core.AnimationFillModeNone
+	AnimationFillModeForwards          = core.AnimationFillModeForwards
+	AnimationFillModeBackwards         = core.AnimationFillModeBackwards
+	AnimationFillModeBoth              = core.AnimationFillModeBoth
+)
+
+// Easing functions.
+var (
+	Linear         Easing = core.Linear
+	EaseIn         Easing = core.EaseIn
+	EaseOut        Easing = core.EaseOut
+	EaseInOut      Easing = core.EaseInOut
+	EaseInCubic    Easing = core.EaseInCubic
+	EaseOutCubic   Easing = core.EaseOutCubic
+	EaseInOutCubic Easing = core.EaseInOutCubic
+	EaseOutBack    Easing = core.EaseOutBack
+	EaseOutElastic Easing = core.EaseOutElastic
+	EaseOutBounce  Easing = core.EaseOutBounce
+)
+
+// CubicBezier returns the easing of a cubic Bézier curve like cubic-bezier() in CSS.
+func CubicBezier(x1, y1, x2, y2 float64) Easing { return core.CubicBezier(x1, y1, x2, y2) }
+
+// RegisterKeyframes registers keyframes so that all views can play them by name.
+// The @keyframes of a parsed document are only played by the views of the document.
+func RegisterKeyframes(k *Keyframes) { core.RegisterKeyframes(k) }
+
+// Sequence returns a group that plays the animations one after another.
+func Sequence(anims ...Animation) *Group { return core.Sequence(anims...) }
+
+// Parallel returns a group that plays the animations at the same time.
+func Parallel(anims ...Animation) *Group { return core.Parallel(anims...) }
+
+// FadeIn returns an enter animation that fades a view in.
+func FadeIn(d time.Duration) func(v *View) Animation { return core.FadeIn(d) }
+
+// FadeOut returns an exit animation that fades a view out.
+func FadeOut(d time.Duration) func(v *View) Animation { return core.FadeOut(d) }
+
+// Rendering and debugging.
+type (
+	Renderer     = core.Renderer
+	ImageOptions = core.ImageOptions
+	LayerOptions = core.LayerOptions
+	Filter       = core.Filter
+	Recorder     = core.Recorder
+	DrawCommand  = core.DrawCommand
+	DrawOp       = core.DrawOp
+	Inspector    = core.Inspector
+	Key          = core.Key
+)
+
+const (
+	FilterNearest    = core.FilterNearest
+	FilterLinear     = core.FilterLinear
+	DrawFillRect     = core.DrawFillRect
+	DrawStrokeRect   = core.DrawStrokeRect
+	DrawFillGradient = core.DrawFillGradient
+	DrawImage        = core.DrawImage
+	DrawText         = core.DrawText
+	DrawPushClip     = core.DrawPushClip
+	DrawPopClip      = core.DrawPopClip
+	DrawBeginLayer   = core.DrawBeginLayer
+	DrawEndLayer     = core.DrawEndLayer
+)
+
+// Keys of key events and Inspector.ToggleKey.
+const (
+	KeyUnknown    = core.KeyUnknown
+	KeyArrowUp    = core.KeyArrowUp
+	KeyArrowDown  = core.KeyArrowDown
+	KeyArrowLeft  = core.KeyArrowLeft
+	KeyArrowRight = core.KeyArrowRight
+	KeyShift      = core.KeyShift
+	KeyControl    = core.KeyControl
+	KeyAlt        = core.KeyAlt
+	KeyEnter      = core.KeyEnter
+	KeyEscape     = core.KeyEscape
+	KeyTab        = core.KeyTab
+	KeySpace      = core.KeySpace
+	KeyBackspace  = core.KeyBackspace
+	KeyF1         = core.KeyF1
+	KeyF2         = core.KeyF2
+	KeyF3         = core.KeyF3
+	KeyF4         = core.KeyF4
+	KeyF5         = core.KeyF5
+	KeyF6         = core.KeyF6
+	KeyF7         = core.KeyF7
+	KeyF8         = core.KeyF8
+	KeyF9         = core.KeyF9
+	KeyF10        = core.KeyF10
+	KeyF11        = core.KeyF11
+	KeyF12        = core.KeyF12
+	KeyA          = core.KeyA
+	KeyB          = core.KeyB
+	KeyC          = core.KeyC
+	KeyD          = core.KeyD
+	KeyE          = core.KeyE
+	KeyF          = core.KeyF
+	KeyG          = core.KeyG
+	KeyH          = core.KeyH
+	KeyI          = core.KeyI
+	KeyJ          = core.KeyJ
+	KeyK          = core.KeyK
+	KeyL          = core.KeyL
+	KeyM          = core.KeyM
+	KeyN          = core.KeyN
+	KeyO          = core.KeyO
+	KeyP          = core.KeyP
+	KeyQ          = core.KeyQ
+	KeyR          = core.KeyR
+	KeyS          = core.KeyS
+	KeyT          = core.KeyT
+	KeyU          = core.KeyU
+	KeyV          = core.KeyV
+	KeyW          = core.KeyW
+	KeyX          = core.KeyX
+	KeyY          = core.KeyY
+	KeyZ          = core.KeyZ
+	Key0          = core.Key0
+	Key1          = core.Key1
+	Key2          = core.Key2
+	Key3          = core.Key3
+	Key4          = core.Key4
+	Key5          = core.Key5
+	Key6          = core.Key6
+	Key7          = core.Key7
+	Key8          = core.Key8
+	Key9          = core.Key9
+)
+
+// NewInspector creates an inspector of the view tree of root. It is toggled with F12.
+func NewInspector(root *View) *Inspector { return core.NewInspector(root) }
@@ -0,0 +1,273 @@
+// Package graphics draws filled and stroked shapes in batches,
+// with one DrawTriangles call for many shapes.
+package graphics
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	maxVertices = math.MaxUint16 + 1
+	// maxArcStep is the maximum length in pixels of a segment of an arc.
+	maxArcStep = 2
+)
+
+// Batch collects shapes and draws them when it is flushed, with as few DrawTriangles
+// calls as the limits on vertices and indices allow. The zero value is an empty batch ready to use.
+// A batch can be kept across frames to reuse its buffers.
+//
+// The strokes of rectangles and circles are inside their outlines like CSS borders;
+// the stroke of a line is centered on the line.
+type Batch struct {
+	chunks []chunk
+	// n is the number of chunks in use.
+	n int
+}
+
+// chunk is the vertices and the indices of a DrawTriangles call.
+type chunk struct {
+	vertices []ebiten.Vertex
+	indices  []uint16
+}
+
+type point struct {
+	x, y float32
+}
+
+// Flush draws the shapes of the batch onto dst and empties the batch.
+func (b *Batch) Flush(dst *ebiten.Image) {
+	for i := 0; i < b.n; i++ {
+		c := &b.chunks[i]
+		dst.DrawTriangles(c.vertices, c.indices, WhiteImage(), &ebiten.DrawTrianglesOptions{})
+		c.vertices, c.indices = c.vertices[:0], c.indices[:0]
+	}
+	b.n = 0
+}
+
+// Reset empties the batch without drawing it.
+func (b *Batch) Reset() {
+	for i := 0; i < b.n; i++ {
+		b.chunks[i].vertices, b.chunks[i].indices = b.chunks[i].vertices[:0], b.chunks[i].indices[:0]
+	}
+	b.n = 0
+}
+
+// FillRect fills a rectangle.
+func (b *Batch) FillRect(x, y, width, height float32, clr color.Color) {
+	if width <= 0 || height <= 0 {
+		return
+	}
+	b.fillPolygon(rectPolygon(x, y, x+width, y+height, 0, 0), clr)
+}
+
+// StrokeRect strokes the inside of a rectangle.
+func (b *Batch) StrokeRect(x, y, width, height, strokeWidth float32, clr color.Color) {
+	b.StrokeRoundedRect(x, y, width, height, 0, strokeWidth, clr)
+}
+
+// FillRoundedRect fills a rectangle with rounded corners.
+func (b *Batch) FillRoundedRect(x, y, width, height, radius float32, clr color.Color) {
+	if width <= 0 || height <= 0 {
+		return
+	}
+	radius = clampRadius(width, height, radius)
+	b.fillPolygon(rectPolygon(x, y, x+width, y+height, radius, arcSegments(radius, math.Pi/2)), clr)
+}
+
+// StrokeRoundedRect strokes the inside of a rectangle with rounded corners.
+// The inner corners are rounded with the radius minus the stroke width.
+func (b *Batch) StrokeRoundedRect(x, y, width, height, radius, strokeWidth float32, clr color.Color) {
+	if width <= 0 || height <= 0 || strokeWidth <= 0 {
+		return
+	}
+	if strokeWidth*2 >= width || strokeWidth*2 >= height {
+		b.FillRoundedRect(x, y, width, height, radius, clr)
+		return
+	}
+	radius = clampRadius(width, height, radius)
+	n := arcSegments(radius, math.Pi/2)
+	inner := radius - strokeWidth
+	if inner < 0 {
+		inner = 0
+	}
+	b.strokeRing(
+		rectPolygon(x, y, x+width, y+height, radius, n),
+		rectPolygon(x+strokeWidth, y+strokeWidth, x+width-strokeWidth, y+height-strokeWidth, inner, n),
+		clr)
+}
+
+// FillCircle fills a circle.
+func (b *Batch) FillCircle(cx, cy, radius float32, clr color.Color) {
+	if radius <= 0 {
+		return
+	}
+	b.fillPolygon(circlePolygon(cx, cy, radius, circleSegments(radius)), clr)
+}
+
+// StrokeCircle strokes the inside of a circle.
+func (b *Batch) StrokeCircle(cx, cy, radius, strokeWidth float32, clr color.Color) {
+	if radius <= 0 || strokeWidth <= 0 {
+		return
+	}
+	if strokeWidth >= radius {
+		b.FillCircle(cx, cy, radius, clr)
+		return
+	}
+	n := circleSegments(radius)
+	b.strokeRing(circlePolygon(cx, cy, radius, n), circlePolygon(cx, cy, radius-strokeWidth, n), clr)
+}
+
+// StrokeLine strokes a line from (x0, y0) to (x1, y1) with butt caps.
+func (b *Batch) StrokeLine(x0, y0, x1, y1, strokeWidth float32, clr color.Color) {
+	l := float32(math.Hypot(float64(x1-x0), float64(y1-y0)))
+	if l == 0 || strokeWidth <= 0 {
+		return
+	}
+	nx, ny := -(y1-y0)/l*strokeWidth/2, (x1-x0)/l*strokeWidth/2
+	b.fillPolygon([]point{
+		{x0 + nx, y0 + ny}, {x1 + nx, y1 + ny}, {x1 - nx, y1 - ny}, {x0 - nx, y0 - ny},
+	}, clr)
+}
+
+// fillPolygon fills a convex polygon as a fan of triangles.
+func (b *Batch) fillPolygon(poly []point, clr color.Color) {
+	c := b.reserve(len(poly), (len(poly)-2)*3)
+	base := uint16(len(c.vertices))
+	c.vertices = appendVertices(c.vertices, poly, clr)
+	for i := 1; i+1 < len(poly); i++ {
+		c.indices = append(c.indices, base, base+uint16(i), base+uint16(i+1))
+	}
+}
+
+// strokeRing fills the area between two closed polygons with the same number of points.
+func (b *Batch) strokeRing(outer, inner []point, clr color.Color) {
+	n := len(outer)
+	c := b.reserve(n*2, n*6)
+	base := uint16(len(c.vertices))
+	c.vertices = appendVertices(c.vertices, outer, clr)
+	c.vertices = appendVertices(c.vertices, inner, clr)
+	for i := 0; i < n; i++ {
+		j := (i + 1) % n
+		o0, o1 := base+uint16(i), base+uint16(j)
+		i0, i1 := base+uint16(n+i), base+uint16(n+j)
+		c.indices = append(c.indices, o0, o1, i1, o0, i1, i0)
+	}
+}
+
+// reserve returns the chunk to which the vertices and the indices of a shape are added.
+func (b *Batch) reserve(vertices, indices int) *chunk {
+	if b.n > 0 {
+		c := &b.chunks[b.n-1]
+		if len(c.vertices)+vertices <= maxVertices && len(c.indices)+indices <= ebiten.MaxIndicesCount {
+			return c
+		}
+	}
+	if b.n == len(b.chunks) {
+		b.chunks = append(b.chunks, chunk{})
+	}
+	b.n++
+	return &b.chunks[b.n-1]
+}
+
+func appendVertices(vs []ebiten.Vertex, poly []point, clr color.Color) []ebiten.Vertex {
+	r, g, b, a := ColorScale(clr)
+	for _, p := range poly {
+		vs = append(vs, ebiten.Vertex{
+			DstX: p.x, DstY: p.y, SrcX: 1, SrcY: 1,
+			ColorR: r, ColorG: g, ColorB: b, ColorA: a,
+		})
+	}
+	return vs
+}
+
+// rectPolygon returns the clockwise polygon of a rectangle with rounded corners.
+// Each corner has n+1 points even if the radius is 0, so that the polygons of
+// the outer and the inner edges of a stroke have the same number of points.
+func rectPolygon(x0, y0, x1, y1, radius float32, n int) []point {
+	corners := []struct {
+		x, y  float32
+		start float64
+	}{
+		{x1 - radius, y0 + radius, -math.Pi / 2},
+		{x1 - radius, y1 - radius, 0},
+		{x0 + radius, y1 - radius, math.Pi / 2},
+		{x0 + radius, y0 + radius, math.Pi},
+	}
+	poly := make([]point, 0, (n+1)*4)
+	for _, c := range corners {
+		for i := 0; i <= n; i++ {
+			a := c.start
+			if n > 0 {
+				a += math.Pi / 2 * float64(i) / float64(n)
+			}
+			poly = append(poly, point{c.x + radius*float32(math.Cos(a)), c.y + radius*float32(math.Sin(a))})
+		}
+	}
+	return poly
+}
+
+func circlePolygon(cx, cy, radius float32, n int) []point {
+	poly := make([]point, n)
+	for i := range poly {
+		a := 2 * math.Pi * float64(i) / float64(n)
+		poly[i] = point{cx + radius*float32(math.Cos(a)), cy + radius*float32(math.Sin(a))}
+	}
+	return poly
+}
+
+// arcSegments returns the number of segments of an arc of the radius and the angle.
+// It is 0 if the radius is 0.
+func arcSegments(radius float32, angle float64) int {
+	if radius <= 0 {
+		return 0
+	}
+	return int(math.Ceil(angle * float64(radius) / maxArcStep))
+}
+
+func circleSegments(radius float32) int {
+	if n := arcSegments(radius, 2*math.Pi); n > 8 {
+		return n
+	}
+	return 8
+}
+
+func clampRadius(width, height, radius float32) float32 {
+	if m := width / 2; radius > m {
+		radius = m
+	}
+	if m := height / 2; radius > m {
+		radius = m
+	}
+	if radius < 0 {
+		return 0
+	}
+	return radius
+}
+
+// ColorScale returns the color as the non-premultiplied color scale of a vertex.
+func ColorScale(c color.Color) (float32, float32, float32, float32) {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return float32(n.R) / 0xff, float32(n.G) / 0xff, float32(n.B) / 0xff, float32(n.A) / 0xff
+}
+
+var (
+	whiteImage    *ebiten.Image
+	whiteSubImage *ebiten.Image
+	whiteOnce     sync.Once
+)
+
+// WhiteImage returns a white image to be used as the source of DrawTriangles.
+// The source position of the vertices is (1, 1).
+func WhiteImage() *ebiten.Image {
+	whiteOnce.Do(func() {
+		whiteImage = ebiten.NewImage(3, 3)
+		whiteImage.Fill(color.White)
+		whiteSubImage = whiteImage.SubImage(image.Rect(1, 1, 2, 2)).(*ebiten.Image)
+	})
+	return whiteSubImage
+}
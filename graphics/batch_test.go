@@ -0,0 +1,90 @@
+package graphics
+
+import (
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// area returns the sum of the areas of the triangles in the batch.
+func area(b *Batch) float64 {
+	a := 0.
+	for _, c := range b.chunks[:b.n] {
+		for i := 0; i < len(c.indices); i += 3 {
+			p, q, r := c.vertices[c.indices[i]], c.vertices[c.indices[i+1]], c.vertices[c.indices[i+2]]
+			cross := (q.DstX-p.DstX)*(r.DstY-p.DstY) - (q.DstY-p.DstY)*(r.DstX-p.DstX)
+			a += math.Abs(float64(cross)) / 2
+		}
+	}
+	return a
+}
+
+func TestStrokeRect(t *testing.T) {
+	b := &Batch{}
+	b.StrokeRect(10, 20, 10, 10, 2, color.White)
+	// The stroke covers each edge once: the outer area minus the inner area.
+	require.InDelta(t, 100-36, area(b), 1e-3)
+
+	maxX := float32(0)
+	for _, v := range b.chunks[0].vertices {
+		maxX = float32(math.Max(float64(maxX), float64(v.DstX)))
+	}
+	require.Equal(t, float32(20), maxX)
+
+	b.Reset()
+	b.StrokeRect(0, 0, 10, 3, 2, color.White)
+	require.InDelta(t, 30, area(b), 1e-3)
+}
+
+func TestFillShapes(t *testing.T) {
+	b := &Batch{}
+	b.FillRect(0, 0, 10, 5, color.White)
+	require.InDelta(t, 50, area(b), 1e-3)
+
+	b.Reset()
+	b.FillRoundedRect(0, 0, 40, 20, 10, color.White)
+	require.InDelta(t, 800-(4-math.Pi)*100, area(b), 3)
+
+	b.Reset()
+	b.StrokeRoundedRect(0, 0, 40, 20, 4, 2, color.White)
+	outer := 800 - (4-math.Pi)*16
+	inner := 36*16 - (4-math.Pi)*4
+	require.InDelta(t, outer-inner, area(b), 1)
+
+	b.Reset()
+	b.FillCircle(50, 50, 20, color.White)
+	require.InDelta(t, math.Pi*400, area(b), math.Pi*400*0.01)
+
+	b.Reset()
+	b.StrokeCircle(50, 50, 20, 5, color.White)
+	require.InDelta(t, math.Pi*(400-225), area(b), math.Pi*175*0.02)
+
+	b.Reset()
+	b.StrokeLine(0, 0, 30, 40, 2, color.White)
+	require.InDelta(t, 100, area(b), 1e-3)
+
+	b.Reset()
+	b.FillRect(0, 0, 0, 10, color.White)
+	b.StrokeLine(1, 1, 1, 1, 2, color.White)
+	require.Equal(t, 0, b.n)
+}
+
+func TestBatchChunks(t *testing.T) {
+	b := &Batch{}
+	rects := ebiten.MaxIndicesCount / 6
+	for i := 0; i < rects+1; i++ {
+		b.FillRect(float32(i%100), float32(i/100), 1, 1, color.White)
+	}
+	require.Equal(t, 2, b.n)
+	require.Len(t, b.chunks[0].indices, rects*6)
+	require.Len(t, b.chunks[1].vertices, 4)
+
+	b.Flush(ebiten.NewImage(100, 100))
+	require.Equal(t, 0, b.n)
+	b.FillRect(0, 0, 1, 1, color.White)
+	require.Equal(t, 1, b.n)
+	require.Len(t, b.chunks[0].vertices, 4)
+}
@@ -4,23 +4,15 @@ import (
 	"image"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/yohamta/furex/v2/core"
 )
 
-// Handler represents a component that can be added to a container.
-type Handler interface{}
-
 // Drawer represents a component that can be added to a container.
 type Drawer interface {
 	// Draw function draws the content of the component inside the frame.
 	Draw(screen *ebiten.Image, frame image.Rectangle, v *View)
 }
 
-// Updater represents a component that updates by one tick.
-type Updater interface {
-	// Update updates the state of the component by one tick.
-	Update(v *View)
-}
-
 // DrawHandler represents a component that can be added to a container.
 // Deprectead: use Drawer instead
 type DrawHandler interface {
@@ -29,13 +21,6 @@ type DrawHandler interface {
 	HandleDraw(screen *ebiten.Image, frame image.Rectangle)
 }
 
-// UpdateHandler represents a component that updates by one tick.
-// Deprectead: use Updater instead
-type UpdateHandler interface {
-	// Updater updates the state of the component by one tick.
-	HandleUpdate()
-}
-
 // ButtonHandler represents a button component.
 type ButtonHandler interface {
 	// HandlePress handle the event when user just started pressing the button
@@ -50,13 +35,6 @@ type ButtonHandler interface {
 	HandleRelease(x, y int, isCancel bool)
 }
 
-// NotButton represents a component that is not a button.
-// TODO: update HandlePress to return bool in the next major version.
-type NotButton interface {
-	// IsButton returns true if the handler is a button.
-	IsButton() bool
-}
-
 // TouchHandler represents a component that handle touches.
 type TouchHandler interface {
 	// HandleJustPressedTouchID handles the touchID just pressed and returns true if it handles the TouchID
@@ -66,46 +44,16 @@ type TouchHandler interface {
 	HandleJustReleasedTouchID(touch ebiten.TouchID, x, y int)
 }
 
-// MouseHandler represents a component that handle mouse move.
-type MouseHandler interface {
-	// HandleMouse handles the mouch move and returns true if it handle the mouse move.
+// MouseButtonHandler represents a component that handles the buttons of the mouse.
+type MouseButtonHandler interface {
+	// HandleJustPressedMouseButton handles the mouse button just pressed inside the view.
 	// The parameter (x, y) is the location relative to the window (0,0).
-	HandleMouse(x, y int) bool
-}
-
-// MouseLeftButtonHandler represents a component that handle mouse button left click.
-type MouseLeftButtonHandler interface {
-	// HandleJustPressedMouseButtonLeft handle left mouse button click just pressed.
-	// The parameter (x, y) is the location relative to the window (0,0).
-	// It returns true if it handles the mouse move.
-	HandleJustPressedMouseButtonLeft(x, y int) bool
-	// HandleJustReleasedTouchID handles the touchID just released.
-	// The parameter (x, y) is the location relative to the window (0,0).
-	HandleJustReleasedMouseButtonLeft(x, y int)
-}
-
-// MouseEnterHandler represets a component that handle mouse enter.
-type MouseEnterLeaveHandler interface {
-	// HandleMouseEnter handles the mouse enter.
-	HandleMouseEnter(x, y int) bool
-	// HandleMouseLeave handles the mouse leave.
-	HandleMouseLeave()
-}
-
-// SwipeHandler represents different swipe directions.
-type SwipeDirection int
-
-const (
-	SwipeDirectionLeft SwipeDirection = iota
-	SwipeDirectionRight
-	SwipeDirectionUp
-	SwipeDirectionDown
-)
-
-// SwipeHandler represents a component that handle swipe.
-type SwipeHandler interface {
-	// HandleSwipe handles swipes.
-	HandleSwipe(dir SwipeDirection)
+	// It returns true if it handles the button.
+	HandleJustPressedMouseButton(b ebiten.MouseButton, x, y int) bool
+	// HandleJustReleasedMouseButton handles the mouse button just released.
+	// It is called only when it handled the button when pressed.
+	// The parameter isCancel is true when the button is released outside of the view.
+	HandleJustReleasedMouseButton(b ebiten.MouseButton, x, y int, isCancel bool)
 }
 
 type handler struct {
@@ -114,10 +62,12 @@ type handler struct {
 
 // HandlerOpts represents the options for a handler.
 type HandlerOpts struct {
-	Update        func(v *View)
-	Draw          func(screen *ebiten.Image, frame image.Rectangle, v *View)
-	HandlePress   func(x, y int, t ebiten.TouchID)
-	HandleRelease func(x, y int, isCancel bool)
+	Update func(v *View)
+	Draw   func(screen *ebiten.Image, frame image.Rectangle, v *View)
+	// DrawWithContext is used instead of Draw if it is set.
+	DrawWithContext func(ctx *DrawContext)
+	HandlePress     func(x, y int, t ebiten.TouchID)
+	HandleRelease   func(x, y int, isCancel bool)
 }
 
 // NewHandler creates a new handler.
@@ -137,6 +87,14 @@ func (h *handler) Draw(screen *ebiten.Image, frame image.Rectangle, v *View) {
 	}
 }
 
+func (h *handler) DrawWithContext(ctx *DrawContext) {
+	if h.opts.DrawWithContext != nil {
+		h.opts.DrawWithContext(ctx)
+		return
+	}
+	h.Draw(Screen(ctx), ctx.Frame, ctx.View)
+}
+
 func (h *handler) HandlePress(x, y int, t ebiten.TouchID) {
 	if h.opts.HandlePress != nil {
 		h.opts.HandlePress(x, y, t)
@@ -148,3 +106,82 @@ func (h *handler) HandleRelease(x, y int, isCancel bool) {
 		h.opts.HandleRelease(x, y, isCancel)
 	}
 }
+
+// Screen returns the screen of the draw context as an ebiten image, or nil if it has none.
+func Screen(ctx *DrawContext) *ebiten.Image {
+	s, _ := ctx.Screen.(*ebiten.Image)
+	return s
+}
+
+// handlerAdapter lets the handlers with the ebiten types handle the views of core.
+type handlerAdapter struct{}
+
+var _ core.HandlerAdapter = handlerAdapter{}
+
+func (handlerAdapter) Draw(h Handler, ctx *DrawContext) bool {
+	switch h := h.(type) {
+	case DrawHandler:
+		h.HandleDraw(Screen(ctx), ctx.Frame)
+	case Drawer:
+		h.Draw(Screen(ctx), ctx.Frame, ctx.View)
+	default:
+		return false
+	}
+	return true
+}
+
+func (handlerAdapter) ButtonHandler(h Handler) (core.ButtonHandler, bool) {
+	b, ok := h.(ButtonHandler)
+	if !ok {
+		return nil, false
+	}
+	return buttonHandler{b}, true
+}
+
+func (handlerAdapter) TouchHandler(h Handler) (core.TouchHandler, bool) {
+	t, ok := h.(TouchHandler)
+	if !ok {
+		return nil, false
+	}
+	return touchHandler{t}, true
+}
+
+func (handlerAdapter) MouseButtonHandler(h Handler) (core.MouseButtonHandler, bool) {
+	m, ok := h.(MouseButtonHandler)
+	if !ok {
+		return nil, false
+	}
+	return mouseButtonHandler{m}, true
+}
+
+type buttonHandler struct {
+	ButtonHandler
+}
+
+func (b buttonHandler) HandlePress(x, y int, t core.TouchID) {
+	b.ButtonHandler.HandlePress(x, y, ebiten.TouchID(t))
+}
+
+type touchHandler struct {
+	TouchHandler
+}
+
+func (t touchHandler) HandleJustPressedTouchID(touch core.TouchID, x, y int) bool {
+	return t.TouchHandler.HandleJustPressedTouchID(ebiten.TouchID(touch), x, y)
+}
+
+func (t touchHandler) HandleJustReleasedTouchID(touch core.TouchID, x, y int) {
+	t.TouchHandler.HandleJustReleasedTouchID(ebiten.TouchID(touch), x, y)
+}
+
+type mouseButtonHandler struct {
+	MouseButtonHandler
+}
+
+func (m mouseButtonHandler) HandleJustPressedMouseButton(b core.MouseButton, x, y int) bool {
+	return m.MouseButtonHandler.HandleJustPressedMouseButton(ebitenMouseButtons[b], x, y)
+}
+
+func (m mouseButtonHandler) HandleJustReleasedMouseButton(b core.MouseButton, x, y int, isCancel bool) {
+	m.MouseButtonHandler.HandleJustReleasedMouseButton(ebitenMouseButtons[b], x, y, isCancel)
+}
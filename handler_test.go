@@ -0,0 +1,81 @@
+package furex
+
+import (
+	"image"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+)
+
+type ebitenButton struct {
+	screen  *ebiten.Image
+	frame   image.Rectangle
+	touchID ebiten.TouchID
+	pressed bool
+}
+
+func (b *ebitenButton) Draw(screen *ebiten.Image, frame image.Rectangle, v *View) {
+	b.screen = screen
+	b.frame = frame
+}
+
+func (b *ebitenButton) HandlePress(x, y int, t ebiten.TouchID) {
+	b.touchID = t
+	b.pressed = true
+}
+
+func (b *ebitenButton) HandleRelease(x, y int, isCancel bool) {
+	b.pressed = false
+}
+
+func TestHandlerAdapter(t *testing.T) {
+	button := &ebitenButton{}
+	root := &View{Width: 100, Height: 100}
+	root.AddChild(&View{Left: 10, Top: 20, Width: 30, Height: 40, Position: PositionAbsolute, Handler: button})
+
+	screen := ebiten.NewImage(100, 100)
+	root.Draw(screen)
+	require.Same(t, screen, button.screen)
+	require.Equal(t, image.Rect(10, 20, 40, 60), button.frame)
+
+	require.True(t, root.HandleJustPressedTouchID(3, 20, 30))
+	require.True(t, button.pressed)
+	require.Equal(t, ebiten.TouchID(3), button.touchID)
+
+	root.HandleJustReleasedTouchID(3, 20, 30)
+	require.False(t, button.pressed)
+}
+
+func TestNewHandler(t *testing.T) {
+	var got *ebiten.Image
+	h := NewHandler(HandlerOpts{
+		Draw: func(screen *ebiten.Image, frame image.Rectangle, v *View) { got = screen },
+	})
+	root := &View{Width: 10, Height: 10}
+	root.AddChild(&View{Width: 10, Height: 10, Handler: h})
+
+	screen := ebiten.NewImage(10, 10)
+	root.Draw(screen)
+	require.Same(t, screen, got)
+}
+
+type ebitenMouseButtonHandler struct {
+	pressed ebiten.MouseButton
+}
+
+func (h *ebitenMouseButtonHandler) HandleJustPressedMouseButton(b ebiten.MouseButton, x, y int) bool {
+	h.pressed = b
+	return true
+}
+
+func (h *ebitenMouseButtonHandler) HandleJustReleasedMouseButton(b ebiten.MouseButton, x, y int, isCancel bool) {
+}
+
+func TestMouseButtonHandlerAdapter(t *testing.T) {
+	h := &ebitenMouseButtonHandler{}
+	m, ok := handlerAdapter{}.MouseButtonHandler(h)
+	require.True(t, ok)
+	require.True(t, m.HandleJustPressedMouseButton(MouseButtonMiddle, 0, 0))
+	require.Equal(t, ebiten.MouseButtonMiddle, h.pressed)
+}
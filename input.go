@@ -0,0 +1,129 @@
+package furex
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/yohamta/furex/v2/core"
+)
+
+// ebitenInput reads the input of ebiten.
+type ebitenInput struct{}
+
+var _ core.Input = ebitenInput{}
+
+var ebitenKeys = map[core.Key]ebiten.Key{
+	core.KeyArrowUp:    ebiten.KeyArrowUp,
+	core.KeyArrowDown:  ebiten.KeyArrowDown,
+	core.KeyArrowLeft:  ebiten.KeyArrowLeft,
+	core.KeyArrowRight: ebiten.KeyArrowRight,
+	core.KeyShift:      ebiten.KeyShift,
+	core.KeyControl:    ebiten.KeyControl,
+	core.KeyAlt:        ebiten.KeyAlt,
+	core.KeyEnter:      ebiten.KeyEnter,
+	core.KeyEscape:     ebiten.KeyEscape,
+	core.KeyTab:        ebiten.KeyTab,
+	core.KeySpace:      ebiten.KeySpace,
+	core.KeyBackspace:  ebiten.KeyBackspace,
+	core.KeyF1:         ebiten.KeyF1,
+	core.KeyF2:         ebiten.KeyF2,
+	core.KeyF3:         ebiten.KeyF3,
+	core.KeyF4:         ebiten.KeyF4,
+	core.KeyF5:         ebiten.KeyF5,
+	core.KeyF6:         ebiten.KeyF6,
+	core.KeyF7:         ebiten.KeyF7,
+	core.KeyF8:         ebiten.KeyF8,
+	core.KeyF9:         ebiten.KeyF9,
+	core.KeyF10:        ebiten.KeyF10,
+	core.KeyF11:        ebiten.KeyF11,
+	core.KeyF12:        ebiten.KeyF12,
+	core.KeyA:          ebiten.KeyA,
+	core.KeyB:          ebiten.KeyB,
+	core.KeyC:          ebiten.KeyC,
+	core.KeyD:          ebiten.KeyD,
+	core.KeyE:          ebiten.KeyE,
+	core.KeyF:          ebiten.KeyF,
+	core.KeyG:          ebiten.KeyG,
+	core.KeyH:          ebiten.KeyH,
+	core.KeyI:          ebiten.KeyI,
+	core.KeyJ:          ebiten.KeyJ,
+	core.KeyK:          ebiten.KeyK,
+	core.KeyL:          ebiten.KeyL,
+	core.KeyM:          ebiten.KeyM,
+	core.KeyN:          ebiten.KeyN,
+	core.KeyO:          ebiten.KeyO,
+	core.KeyP:          ebiten.KeyP,
+	core.KeyQ:          ebiten.KeyQ,
+	core.KeyR:          ebiten.KeyR,
+	core.KeyS:          ebiten.KeyS,
+	core.KeyT:          ebiten.KeyT,
+	core.KeyU:          ebiten.KeyU,
+	core.KeyV:          ebiten.KeyV,
+	core.KeyW:          ebiten.KeyW,
+	core.KeyX:          ebiten.KeyX,
+	core.KeyY:          ebiten.KeyY,
+	core.KeyZ:          ebiten.KeyZ,
+	core.Key0:          ebiten.KeyDigit0,
+	core.Key1:          ebiten.KeyDigit1,
+	core.Key2:          ebiten.KeyDigit2,
+	core.Key3:          ebiten.KeyDigit3,
+	core.Key4:          ebiten.KeyDigit4,
+	core.Key5:          ebiten.KeyDigit5,
+	core.Key6:          ebiten.KeyDigit6,
+	core.Key7:          ebiten.KeyDigit7,
+	core.Key8:          ebiten.KeyDigit8,
+	core.Key9:          ebiten.KeyDigit9,
+}
+
+var ebitenMouseButtons = map[core.MouseButton]ebiten.MouseButton{
+	core.MouseButtonLeft:   ebiten.MouseButtonLeft,
+	core.MouseButtonRight:  ebiten.MouseButtonRight,
+	core.MouseButtonMiddle: ebiten.MouseButtonMiddle,
+}
+
+func (ebitenInput) CursorPosition() (int, int) {
+	return ebiten.CursorPosition()
+}
+
+func (ebitenInput) Wheel() (float64, float64) {
+	return ebiten.Wheel()
+}
+
+func (ebitenInput) IsMouseButtonPressed(b core.MouseButton) bool {
+	eb, ok := ebitenMouseButtons[b]
+	return ok && ebiten.IsMouseButtonPressed(eb)
+}
+
+func (ebitenInput) IsMouseButtonJustPressed(b core.MouseButton) bool {
+	eb, ok := ebitenMouseButtons[b]
+	return ok && inpututil.IsMouseButtonJustPressed(eb)
+}
+
+func (ebitenInput) IsMouseButtonJustReleased(b core.MouseButton) bool {
+	eb, ok := ebitenMouseButtons[b]
+	return ok && inpututil.IsMouseButtonJustReleased(eb)
+}
+
+func (ebitenInput) AppendJustPressedTouchIDs(ids []core.TouchID) []core.TouchID {
+	for _, id := range inpututil.AppendJustPressedTouchIDs(nil) {
+		ids = append(ids, core.TouchID(id))
+	}
+	return ids
+}
+
+func (ebitenInput) IsTouchJustReleased(id core.TouchID) bool {
+	return inpututil.IsTouchJustReleased(ebiten.TouchID(id))
+}
+
+func (ebitenInput) TouchPosition(id core.TouchID) (int, int) {
+	return ebiten.TouchPosition(ebiten.TouchID(id))
+}
+
+func (ebitenInput) IsKeyPressed(k core.Key) bool {
+	ek, ok := ebitenKeys[k]
+	return ok && ebiten.IsKeyPressed(ek)
+}
+
+func (ebitenInput) IsKeyJustPressed(k core.Key) bool {
+	ek, ok := ebitenKeys[k]
+	return ok && inpututil.IsKeyJustPressed(ek)
+}
@@ -0,0 +1,273 @@
+package graphic
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/yohamta/furex/v2/graphics"
+)
+
+// GradientStop is a color at an offset along a gradient.
+// The offset is 0 at the start of the gradient and 1 at its end.
+type GradientStop struct {
+	Offset float32
+	Color  color.Color
+}
+
+type LinearGradientOpts struct {
+	Rect   image.Rectangle
+	Radius float32
+	// Angle is the direction of the gradient in degrees as in CSS:
+	// 0 goes to the top, 90 to the right and 180 to the bottom.
+	Angle float64
+	Stops []GradientStop
+}
+
+type RadialGradientOpts struct {
+	Rect   image.Rectangle
+	Radius float32
+	// Circle makes the gradient a circle instead of an ellipse.
+	// Either way the gradient ends at the farthest corner of Rect.
+	Circle bool
+	Stops  []GradientStop
+}
+
+type point struct {
+	x, y float32
+}
+
+// DrawLinearGradient fills a rectangle with rounded corners with a linear gradient.
+func DrawLinearGradient(target *ebiten.Image, opts *LinearGradientOpts) {
+	stops := sortStops(opts.Stops)
+	poly := roundedRectPolygon(opts.Rect, opts.Radius, 0)
+	if len(stops) == 0 || len(poly) < 3 {
+		return
+	}
+	rad := opts.Angle * math.Pi / 180
+	dx, dy := float32(math.Sin(rad)), float32(-math.Cos(rad))
+	w, h := float32(opts.Rect.Dx()), float32(opts.Rect.Dy())
+	length := abs(w*dx) + abs(h*dy)
+	cx, cy := float32(opts.Rect.Min.X)+w/2, float32(opts.Rect.Min.Y)+h/2
+	offset := func(p point) float32 {
+		return ((p.x-cx)*dx+(p.y-cy)*dy)/length + .5
+	}
+
+	// The colors are linear between two stops, so each band between two stops
+	// is drawn as a convex polygon with the colors of its vertices.
+	var vs []ebiten.Vertex
+	var is []uint16
+	bounds := make([]float32, 0, len(stops)+2)
+	bounds = append(bounds, float32(math.Inf(-1)))
+	for _, s := range stops {
+		bounds = append(bounds, s.Offset)
+	}
+	bounds = append(bounds, float32(math.Inf(1)))
+	for i := 0; i+1 < len(bounds); i++ {
+		band := clipPolygon(poly, offset, bounds[i], bounds[i+1])
+		if len(band) < 3 {
+			continue
+		}
+		base := uint16(len(vs))
+		for _, p := range band {
+			vs = append(vs, vertex(p, colorAt(stops, offset(p))))
+		}
+		for j := 1; j+1 < len(band); j++ {
+			is = append(is, base, base+uint16(j), base+uint16(j+1))
+		}
+		if len(vs) > math.MaxUint16-len(poly)*2 {
+			break
+		}
+	}
+	target.DrawTriangles(vs, is, graphics.WhiteImage(), &ebiten.DrawTrianglesOptions{})
+}
+
+// DrawRadialGradient fills a rectangle with rounded corners with a radial gradient
+// centered in the rectangle.
+func DrawRadialGradient(target *ebiten.Image, opts *RadialGradientOpts) {
+	stops := sortStops(opts.Stops)
+	poly := roundedRectPolygon(opts.Rect, opts.Radius, 16)
+	if len(stops) == 0 || len(poly) < 3 {
+		return
+	}
+	w, h := float32(opts.Rect.Dx()), float32(opts.Rect.Dy())
+	cx, cy := float32(opts.Rect.Min.X)+w/2, float32(opts.Rect.Min.Y)+h/2
+	rx, ry := w/2*math.Sqrt2, h/2*math.Sqrt2
+	if opts.Circle {
+		rx = float32(math.Hypot(float64(w/2), float64(h/2)))
+		ry = rx
+	}
+	offset := func(p point) float32 {
+		return float32(math.Hypot(float64((p.x-cx)/rx), float64((p.y-cy)/ry)))
+	}
+	center := point{cx, cy}
+	// along is the point at the offset o on the ray from the center to p.
+	along := func(p point, po, o float32) point {
+		if o >= po || po == 0 {
+			return p
+		}
+		return point{cx + (p.x-cx)*o/po, cy + (p.y-cy)*o/po}
+	}
+
+	// The polygon is drawn as a fan of sectors from the center.
+	// The offset is linear along each ray, so each sector is split into rings at the stops.
+	var vs []ebiten.Vertex
+	var is []uint16
+	for i := range poly {
+		p, q := poly[i], poly[(i+1)%len(poly)]
+		po, qo := offset(p), offset(q)
+		levels := []float32{0}
+		for _, s := range stops {
+			if s.Offset > 0 && (s.Offset < po || s.Offset < qo) {
+				levels = append(levels, s.Offset)
+			}
+		}
+		levels = append(levels, po, qo)
+		sort.Slice(levels, func(a, b int) bool { return levels[a] < levels[b] })
+		prevP, prevQ := center, center
+		for _, l := range levels[1:] {
+			np, nq := along(p, po, l), along(q, qo, l)
+			base := uint16(len(vs))
+			for _, v := range []point{prevP, prevQ, nq, np} {
+				vs = append(vs, vertex(v, colorAt(stops, offset(v))))
+			}
+			is = append(is, base, base+1, base+2, base, base+2, base+3)
+			prevP, prevQ = np, nq
+		}
+		if len(vs) > math.MaxUint16-4*(len(stops)+3) {
+			break
+		}
+	}
+	target.DrawTriangles(vs, is, graphics.WhiteImage(), &ebiten.DrawTrianglesOptions{})
+}
+
+func vertex(p point, c color.Color) ebiten.Vertex {
+	r, g, b, a := graphics.ColorScale(c)
+	return ebiten.Vertex{
+		DstX: p.x, DstY: p.y, SrcX: 1, SrcY: 1,
+		ColorR: r, ColorG: g, ColorB: b, ColorA: a,
+	}
+}
+
+func sortStops(stops []GradientStop) []GradientStop {
+	sorted := append([]GradientStop(nil), stops...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Offset < sorted[j].Offset })
+	return sorted
+}
+
+// colorAt returns the color of sorted stops at the offset.
+// The colors are interpolated with premultiplied alpha.
+func colorAt(stops []GradientStop, offset float32) color.Color {
+	if offset <= stops[0].Offset {
+		return stops[0].Color
+	}
+	for i := 1; i < len(stops); i++ {
+		s0, s1 := stops[i-1], stops[i]
+		if offset > s1.Offset {
+			continue
+		}
+		if s1.Offset == s0.Offset {
+			return s1.Color
+		}
+		t := (offset - s0.Offset) / (s1.Offset - s0.Offset)
+		r0, g0, b0, a0 := s0.Color.RGBA()
+		r1, g1, b1, a1 := s1.Color.RGBA()
+		lerp := func(a, b uint32) uint16 {
+			return uint16(float32(a) + (float32(b)-float32(a))*t)
+		}
+		return color.RGBA64{lerp(r0, r1), lerp(g0, g1), lerp(b0, b1), lerp(a0, a1)}
+	}
+	return stops[len(stops)-1].Color
+}
+
+// roundedRectPolygon returns the clockwise convex polygon of a rectangle with rounded corners.
+// If maxEdge is not 0, the straight edges are split so that no segment is longer than it.
+func roundedRectPolygon(rect image.Rectangle, radius, maxEdge float32) []point {
+	x0, y0 := float32(rect.Min.X), float32(rect.Min.Y)
+	x1, y1 := float32(rect.Max.X), float32(rect.Max.Y)
+	if x1 <= x0 || y1 <= y0 {
+		return nil
+	}
+	if m := (x1 - x0) / 2; radius > m {
+		radius = m
+	}
+	if m := (y1 - y0) / 2; radius > m {
+		radius = m
+	}
+	if radius < 0 {
+		radius = 0
+	}
+	corners := []struct {
+		x, y  float32
+		start float64
+	}{
+		{x1 - radius, y0 + radius, -math.Pi / 2},
+		{x1 - radius, y1 - radius, 0},
+		{x0 + radius, y1 - radius, math.Pi / 2},
+		{x0 + radius, y0 + radius, math.Pi},
+	}
+	const arcSegments = 8
+	var poly []point
+	for _, c := range corners {
+		n := arcSegments
+		if radius == 0 {
+			n = 0
+		}
+		for i := 0; i <= n; i++ {
+			a := c.start + math.Pi/2*float64(i)/arcSegments
+			p := point{c.x + radius*float32(math.Cos(a)), c.y + radius*float32(math.Sin(a))}
+			if maxEdge > 0 && i == 0 && len(poly) > 0 {
+				poly = appendSplitEdge(poly, p, maxEdge)
+			}
+			poly = append(poly, p)
+		}
+	}
+	if maxEdge > 0 {
+		poly = appendSplitEdge(poly, poly[0], maxEdge)
+	}
+	return poly
+}
+
+// appendSplitEdge appends the points that split the edge from the last point of poly to p.
+func appendSplitEdge(poly []point, p point, maxEdge float32) []point {
+	last := poly[len(poly)-1]
+	n := int(math.Ceil(math.Hypot(float64(p.x-last.x), float64(p.y-last.y)) / float64(maxEdge)))
+	for i := 1; i < n; i++ {
+		t := float32(i) / float32(n)
+		poly = append(poly, point{last.x + (p.x-last.x)*t, last.y + (p.y-last.y)*t})
+	}
+	return poly
+}
+
+// clipPolygon clips a convex polygon to the points whose value of the linear function f
+// is between min and max.
+func clipPolygon(poly []point, f func(point) float32, min, max float32) []point {
+	poly = clipHalfPlane(poly, func(p point) float32 { return f(p) - min })
+	return clipHalfPlane(poly, func(p point) float32 { return max - f(p) })
+}
+
+// clipHalfPlane keeps the part of a convex polygon where the linear function d is not negative.
+func clipHalfPlane(poly []point, d func(point) float32) []point {
+	var out []point
+	for i := range poly {
+		p, q := poly[i], poly[(i+1)%len(poly)]
+		dp, dq := d(p), d(q)
+		if dp >= 0 {
+			out = append(out, p)
+		}
+		if (dp >= 0) != (dq >= 0) {
+			t := dp / (dp - dq)
+			out = append(out, point{p.x + (q.x-p.x)*t, p.y + (q.y-p.y)*t})
+		}
+	}
+	return out
+}
+
+func abs(f float32) float32 {
+	if f < 0 {
+		return -f
+	}
+	return f
+}
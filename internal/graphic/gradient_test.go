@@ -0,0 +1,44 @@
+package graphic
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestColorAt(t *testing.T) {
+	stops := []GradientStop{
+		{Offset: .2, Color: color.RGBA{0, 0, 0, 0xff}},
+		{Offset: .6, Color: color.RGBA{0xff, 0xff, 0xff, 0xff}},
+	}
+	gray := func(c color.Color) uint8 { return color.GrayModel.Convert(c).(color.Gray).Y }
+	require.Equal(t, uint8(0), gray(colorAt(stops, 0)))
+	require.Equal(t, uint8(0x7f), gray(colorAt(stops, .4)))
+	require.Equal(t, uint8(0xff), gray(colorAt(stops, 1)))
+}
+
+func TestClipPolygon(t *testing.T) {
+	square := roundedRectPolygon(image.Rect(0, 0, 10, 10), 0, 0)
+	require.Len(t, square, 4)
+
+	band := clipPolygon(square, func(p point) float32 { return p.x }, 2, 5)
+	require.Len(t, band, 4)
+	for _, p := range band {
+		require.True(t, p.x >= 2 && p.x <= 5, p)
+	}
+	require.Empty(t, clipPolygon(square, func(p point) float32 { return p.x }, 20, 30))
+}
+
+func TestRoundedRectPolygon(t *testing.T) {
+	poly := roundedRectPolygon(image.Rect(0, 0, 40, 20), 100, 0)
+	require.Len(t, poly, 36)
+	for _, p := range poly {
+		require.True(t, p.x >= -1e-3 && p.x <= 40+1e-3 && p.y >= -1e-3 && p.y <= 20+1e-3, p)
+	}
+
+	split := roundedRectPolygon(image.Rect(0, 0, 40, 20), 0, 10)
+	require.Len(t, split, 12)
+	require.Nil(t, roundedRectPolygon(image.Rectangle{}, 0, 0))
+}
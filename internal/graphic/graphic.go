@@ -3,40 +3,19 @@ package graphic
 import (
 	"image"
 	"image/color"
-	"sync"
 
-	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/yohamta/furex/v2/graphics"
 )
 
-var (
-	g    graphic
-	once sync.Once
-)
-
-type graphic struct {
-	imgOfAPixel *ebiten.Image
-}
-
-func (g *graphic) setup() {
-	once.Do(func() {
-		g.imgOfAPixel = ebiten.NewImage(1, 1)
-	})
-}
-
 type FillRectOpts struct {
 	Rect  image.Rectangle
 	Color color.Color
 }
 
-func FillRect(target *ebiten.Image, opts *FillRectOpts) {
-	g.setup()
-	r, c := &opts.Rect, &opts.Color
-	g.imgOfAPixel.Fill(*c)
-	op := &ebiten.DrawImageOptions{}
-	w, h := r.Size().X, r.Size().Y
-	op.GeoM.Translate(float64(r.Min.X)*(1/float64(w)), float64(r.Min.Y)*(1/float64(h)))
-	op.GeoM.Scale(float64(w), float64(h))
-	target.DrawImage(g.imgOfAPixel, op)
+// FillRect adds a filled rectangle to the batch.
+func FillRect(b *graphics.Batch, opts *FillRectOpts) {
+	r := opts.Rect
+	b.FillRect(float32(r.Min.X), float32(r.Min.Y), float32(r.Dx()), float32(r.Dy()), opts.Color)
 }
 
 type DrawRectOpts struct {
@@ -45,19 +24,28 @@ type DrawRectOpts struct {
 	StrokeWidth int
 }
 
-func DrawRect(target *ebiten.Image, opts *DrawRectOpts) {
-	g.setup()
-	r, c, sw := &opts.Rect, &opts.Color, opts.StrokeWidth
-	FillRect(target, &FillRectOpts{
-		Rect: image.Rect(r.Min.X, r.Min.Y, r.Min.X+sw, r.Max.Y), Color: *c,
-	})
-	FillRect(target, &FillRectOpts{
-		Rect: image.Rect(r.Min.X, r.Min.Y, r.Min.X+sw, r.Max.Y), Color: *c,
-	})
-	FillRect(target, &FillRectOpts{
-		Rect: image.Rect(r.Min.X, r.Min.Y, r.Max.X, r.Min.Y+sw), Color: *c,
-	})
-	FillRect(target, &FillRectOpts{
-		Rect: image.Rect(r.Min.X, r.Max.Y-sw, r.Max.X, r.Max.Y), Color: *c,
-	})
+// DrawRect adds the stroke of the inside of a rectangle to the batch.
+func DrawRect(b *graphics.Batch, opts *DrawRectOpts) {
+	r := opts.Rect
+	b.StrokeRect(float32(r.Min.X), float32(r.Min.Y), float32(r.Dx()), float32(r.Dy()),
+		float32(opts.StrokeWidth), opts.Color)
+}
+
+type RoundedRectOpts struct {
+	Rect   image.Rectangle
+	Radius float32
+	Color  color.Color
+	// StrokeWidth is the width of the outline. If it is 0, the rectangle is filled.
+	StrokeWidth float32
+}
+
+// DrawRoundedRect adds a filled or stroked rectangle with rounded corners to the batch.
+func DrawRoundedRect(b *graphics.Batch, opts *RoundedRectOpts) {
+	r := opts.Rect
+	x, y, w, h := float32(r.Min.X), float32(r.Min.Y), float32(r.Dx()), float32(r.Dy())
+	if opts.StrokeWidth > 0 {
+		b.StrokeRoundedRect(x, y, w, h, opts.Radius, opts.StrokeWidth, opts.Color)
+		return
+	}
+	b.FillRoundedRect(x, y, w, h, opts.Radius, opts.Color)
 }
@@ -0,0 +1,305 @@
+package furex
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/yohamta/furex/v2/core"
+	"github.com/yohamta/furex/v2/graphics"
+	"github.com/yohamta/furex/v2/internal/graphic"
+	"golang.org/x/image/font"
+)
+
+// ebitenRenderer draws to an ebiten image. Drawing is skipped if the image is nil.
+type ebitenRenderer struct {
+	// targets is the stack of the images drawn to: the screen, its clipped
+	// sub images and the layers.
+	targets []*ebiten.Image
+	// layers are the images of the layers, reused across frames by depth.
+	layers []*ebiten.Image
+	depth  int
+	// batch collects the rectangles filled and stroked in a row,
+	// which are drawn with one DrawTriangles call when another operation begins.
+	batch   graphics.Batch
+	batched bool
+	// flushes is the number of times the batch has been drawn.
+	flushes int
+	// offscreens are the renderers of the offscreen images, reused across redraws.
+	offscreens map[*ebiten.Image]*ebitenRenderer
+}
+
+var (
+	_ core.ScreenRenderer = (*ebitenRenderer)(nil)
+	_ core.CacheRenderer  = (*ebitenRenderer)(nil)
+	_ core.Flusher        = (*ebitenRenderer)(nil)
+)
+
+func newEbitenRenderer(screen *ebiten.Image) *ebitenRenderer {
+	r := &ebitenRenderer{}
+	r.reset(screen)
+	return r
+}
+
+// Reset makes the renderer draw to the screen, which must be an *ebiten.Image.
+func (r *ebitenRenderer) Reset(screen core.Image) {
+	s, _ := screen.(*ebiten.Image)
+	r.reset(s)
+}
+
+func (r *ebitenRenderer) reset(screen *ebiten.Image) {
+	r.batch.Reset()
+	r.batched = false
+	r.targets = append(r.targets[:0], screen)
+	r.depth = 0
+}
+
+func (r *ebitenRenderer) target() *ebiten.Image {
+	return r.targets[len(r.targets)-1]
+}
+
+func (r *ebitenRenderer) Target() core.Image {
+	r.Flush()
+	if t := r.target(); t != nil {
+		return t
+	}
+	return nil
+}
+
+func (r *ebitenRenderer) FillRect(rect image.Rectangle, radius float32, clr color.Color) {
+	if r.target() != nil {
+		graphic.DrawRoundedRect(&r.batch, &graphic.RoundedRectOpts{Rect: rect, Radius: radius, Color: clr})
+		r.batched = true
+	}
+}
+
+func (r *ebitenRenderer) StrokeRect(rect image.Rectangle, radius, width float32, clr color.Color) {
+	if r.target() != nil {
+		graphic.DrawRoundedRect(&r.batch, &graphic.RoundedRectOpts{Rect: rect, Radius: radius, Color: clr, StrokeWidth: width})
+		r.batched = true
+	}
+}
+
+// Flush draws the batched rectangles.
+func (r *ebitenRenderer) Flush() {
+	if !r.batched {
+		return
+	}
+	r.batched = false
+	r.batch.Flush(r.target())
+	r.flushes++
+}
+
+func (r *ebitenRenderer) FillGradient(rect image.Rectangle, radius float32, g *core.Gradient) {
+	r.Flush()
+	t := r.target()
+	if t == nil {
+		return
+	}
+	stops := make([]graphic.GradientStop, len(g.Stops))
+	for i, s := range g.Stops {
+		stops[i] = graphic.GradientStop{Offset: float32(s.Offset), Color: s.Color}
+	}
+	switch g.Kind {
+	case core.GradientLinear:
+		graphic.DrawLinearGradient(t, &graphic.LinearGradientOpts{
+			Rect: rect, Radius: radius, Angle: g.Angle, Stops: stops,
+		})
+	case core.GradientRadial:
+		graphic.DrawRadialGradient(t, &graphic.RadialGradientOpts{
+			Rect: rect, Radius: radius, Circle: g.Circle, Stops: stops,
+		})
+	}
+}
+
+func (r *ebitenRenderer) DrawImage(img core.Image, src, dst image.Rectangle, opts *core.ImageOptions) {
+	r.Flush()
+	t := r.target()
+	if t == nil || src.Empty() {
+		return
+	}
+	e := toEbitenImage(img)
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(float64(dst.Dx())/float64(src.Dx()), float64(dst.Dy())/float64(src.Dy()))
+	op.GeoM.Translate(float64(dst.Min.X), float64(dst.Min.Y))
+	if opts != nil {
+		if opts.Tint != nil {
+			op.ColorM.ScaleWithColor(opts.Tint)
+		}
+		op.Filter = toEbitenFilter(opts.Filter)
+	}
+	if src != e.Bounds() {
+		e = e.SubImage(src).(*ebiten.Image)
+	}
+	t.DrawImage(e, op)
+}
+
+func (r *ebitenRenderer) DrawText(s string, face font.Face, x, y int, clr color.Color) {
+	r.Flush()
+	if t := r.target(); t != nil {
+		text.Draw(t, s, face, x, y, clr)
+	}
+}
+
+func (r *ebitenRenderer) PushClip(rect image.Rectangle) {
+	r.Flush()
+	t := r.target()
+	if t != nil {
+		t = t.SubImage(rect).(*ebiten.Image)
+	}
+	r.targets = append(r.targets, t)
+}
+
+func (r *ebitenRenderer) PopClip() {
+	r.Flush()
+	r.targets = r.targets[:len(r.targets)-1]
+}
+
+func (r *ebitenRenderer) BeginLayer() {
+	r.Flush()
+	t := r.target()
+	if t == nil {
+		r.targets = append(r.targets, nil)
+		return
+	}
+	b := t.Bounds()
+	if r.depth == len(r.layers) {
+		r.layers = append(r.layers, nil)
+	}
+	layer := r.layers[r.depth]
+	if layer != nil {
+		if w, h := layer.Size(); w != b.Max.X || h != b.Max.Y {
+			layer.Dispose()
+			layer = nil
+		}
+	}
+	if layer == nil {
+		layer = ebiten.NewImage(b.Max.X, b.Max.Y)
+		r.layers[r.depth] = layer
+	}
+	layer.Clear()
+	r.depth++
+	r.targets = append(r.targets, layer)
+}
+
+func (r *ebitenRenderer) EndLayer(opts *core.LayerOptions) {
+	r.Flush()
+	layer := r.target()
+	r.targets = r.targets[:len(r.targets)-1]
+	t := r.target()
+	if layer == nil || t == nil {
+		return
+	}
+	r.depth--
+	op := &ebiten.DrawImageOptions{
+		GeoM:   toEbitenGeoM(opts.GeoM),
+		ColorM: toEbitenColorM(opts.ColorM),
+		Filter: toEbitenFilter(opts.Filter),
+	}
+	op.ColorM.Scale(1, 1, 1, opts.Alpha)
+	t.DrawImage(layer, op)
+}
+
+func (r *ebitenRenderer) NewOffscreen(width, height int) core.Image {
+	return ebiten.NewImage(width, height)
+}
+
+func (r *ebitenRenderer) DisposeOffscreen(img core.Image) {
+	e := img.(*ebiten.Image)
+	delete(r.offscreens, e)
+	e.Dispose()
+}
+
+func (r *ebitenRenderer) Offscreen(img core.Image) core.Renderer {
+	e := img.(*ebiten.Image)
+	e.Clear()
+	o, ok := r.offscreens[e]
+	if !ok {
+		if r.offscreens == nil {
+			r.offscreens = map[*ebiten.Image]*ebitenRenderer{}
+		}
+		o = newEbitenRenderer(e)
+		r.offscreens[e] = o
+		return o
+	}
+	o.reset(e)
+	return o
+}
+
+// maxEbitenImages is the number of converted images kept.
+// The least recently used image is disposed to convert another one.
+const maxEbitenImages = 256
+
+type ebitenImage struct {
+	img     *ebiten.Image
+	lastUse int
+}
+
+// ebitenImages are the ebiten images of the images that are not ebiten images,
+// such as the images loaded by LoadImages and the shadow textures.
+var (
+	ebitenImages    = map[core.Image]*ebitenImage{}
+	ebitenImageUses int
+)
+
+// toEbitenImage returns the image as an ebiten image. An image.Image is converted once.
+func toEbitenImage(img core.Image) *ebiten.Image {
+	if e, ok := img.(*ebiten.Image); ok {
+		return e
+	}
+	ebitenImageUses++
+	if e, ok := ebitenImages[img]; ok {
+		e.lastUse = ebitenImageUses
+		return e.img
+	}
+	src, ok := img.(image.Image)
+	if !ok {
+		panic(fmt.Sprintf("furex: cannot draw an image of type %T", img))
+	}
+	if len(ebitenImages) >= maxEbitenImages {
+		var oldest core.Image
+		lastUse := ebitenImageUses
+		for k, e := range ebitenImages {
+			if e.lastUse < lastUse {
+				oldest, lastUse = k, e.lastUse
+			}
+		}
+		ebitenImages[oldest].img.Dispose()
+		delete(ebitenImages, oldest)
+	}
+	e := ebiten.NewImageFromImage(src)
+	ebitenImages[img] = &ebitenImage{img: e, lastUse: ebitenImageUses}
+	return e
+}
+
+func toEbitenFilter(f core.Filter) ebiten.Filter {
+	if f == core.FilterLinear {
+		return ebiten.FilterLinear
+	}
+	return ebiten.FilterNearest
+}
+
+func toEbitenGeoM(g core.GeoM) ebiten.GeoM {
+	var e ebiten.GeoM
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 3; j++ {
+			e.SetElement(i, j, g.Element(i, j))
+		}
+	}
+	return e
+}
+
+func toEbitenColorM(c core.ColorM) ebiten.ColorM {
+	var e ebiten.ColorM
+	if c.IsIdentity() {
+		return e
+	}
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 5; j++ {
+			e.SetElement(i, j, c.Element(i, j))
+		}
+	}
+	return e
+}
@@ -0,0 +1,85 @@
+package furex
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEbitenRenderer(t *testing.T) {
+	screen := ebiten.NewImage(100, 100)
+	r := newEbitenRenderer(screen)
+
+	r.PushClip(image.Rect(10, 10, 50, 50))
+	require.Equal(t, image.Rect(10, 10, 50, 50), r.Target().Bounds())
+	r.BeginLayer()
+	require.Equal(t, image.Rect(0, 0, 50, 50), r.Target().Bounds())
+	r.FillRect(image.Rect(10, 10, 20, 20), 0, color.White)
+	r.EndLayer(&LayerOptions{Alpha: 1})
+	r.PopClip()
+	require.Same(t, screen, r.target())
+
+	// A renderer without a screen draws nothing.
+	r.reset(nil)
+	r.PushClip(image.Rect(0, 0, 10, 10))
+	r.BeginLayer()
+	r.FillRect(image.Rect(0, 0, 10, 10), 0, color.White)
+	r.DrawImage(screen, screen.Bounds(), image.Rect(0, 0, 10, 10), nil)
+	r.EndLayer(&LayerOptions{Alpha: 1})
+	r.PopClip()
+	require.Nil(t, r.Target())
+	require.Zero(t, r.depth)
+}
+
+func TestEbitenRendererBatchesRects(t *testing.T) {
+	root := &View{Width: 100, Height: 100, BackgroundColor: color.White}
+	for i := 0; i < 20; i++ {
+		root.AddChild(&View{Width: 5, Height: 5, BackgroundColor: color.Black, BorderWidth: 1, BorderColor: color.White})
+	}
+	r := newEbitenRenderer(ebiten.NewImage(100, 100))
+	root.Render(r)
+	require.Equal(t, 1, r.flushes, "the boxes of the tree are drawn with one batch")
+
+	root.AddChild(&View{Width: 5, Height: 5, Text: "a"}, &View{Width: 5, Height: 5, BackgroundColor: color.Black})
+	r.flushes = 0
+	root.Render(r)
+	require.Equal(t, 2, r.flushes, "the batch is drawn before the text and after it")
+}
+
+func TestEbitenRendererBatchesDebugBorders(t *testing.T) {
+	Debug = true
+	defer func() { Debug = false }()
+	root := &View{Width: 100, Height: 100}
+	for i := 0; i < 10; i++ {
+		root.AddChild(&View{Width: 5, Height: 5})
+	}
+	r := newEbitenRenderer(ebiten.NewImage(100, 100))
+	root.Render(r)
+	require.Equal(t, 10+1, r.flushes, "one batch before each label and one for all the borders")
+}
+
+func TestEbitenRendererOffscreen(t *testing.T) {
+	r := newEbitenRenderer(ebiten.NewImage(100, 100))
+	img := r.NewOffscreen(10, 10)
+	o := r.Offscreen(img)
+	require.Same(t, o, r.Offscreen(img), "the renderer of an offscreen image is reused")
+	r.DisposeOffscreen(img)
+	require.Empty(t, r.offscreens)
+}
+
+func TestToEbitenImage(t *testing.T) {
+	first := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	e := toEbitenImage(first)
+	require.Same(t, e, toEbitenImage(first))
+
+	// The least recently used images are removed first.
+	for i := 0; i < maxEbitenImages; i++ {
+		toEbitenImage(image.NewRGBA(image.Rect(0, 0, 1, 1)))
+		toEbitenImage(first)
+	}
+	require.Len(t, ebitenImages, maxEbitenImages)
+	require.Same(t, e, toEbitenImage(first))
+}